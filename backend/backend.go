@@ -0,0 +1,159 @@
+// Package backend defines the interface gotranslate's output targets
+// implement, and the tree type they walk to do it.
+//
+// A Backend only ever sees a freshly-decoded *Block, not package superast's
+// internal AST: the super-AST is meant to be a portable intermediate
+// representation (that's the point of emitting it as JSON in the first
+// place), so a backend should be able to consume it without depending on
+// the visitor that produced it. Decode reconstructs the polymorphic
+// "statements"/"arguments" entries (plain interface{} in the JSON) into
+// their concrete Go type by sniffing each node's "type" field.
+package backend
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// Backend renders a decoded super-AST document to w in some target
+// language.
+type Backend interface {
+	Emit(w io.Writer, b *Block) error
+}
+
+type Block struct {
+	ID    int    `json:"id"`
+	Stmts []Stmt `json:"statements"`
+}
+
+type DataType struct {
+	ID      int       `json:"id"`
+	Name    string    `json:"name"`
+	SubType *DataType `json:"data-type,omitempty"`
+}
+
+type VarDecl struct {
+	ID       int       `json:"id"`
+	Name     string    `json:"name"`
+	DataType *DataType `json:"data-type,omitempty"`
+}
+
+// Stmt is any of *Statement, *StructDecl or *ErrorNode.
+type Stmt interface{}
+
+type Statement struct {
+	ID       int          `json:"id"`
+	Line     int          `json:"line"`
+	Type     string       `json:"type"`
+	Name     string       `json:"name,omitempty"`
+	Value    string       `json:"value,omitempty"`
+	DataType *DataType    `json:"data-type,omitempty"`
+	RetType  *DataType    `json:"return-type,omitempty"`
+	Params   []VarDecl    `json:"parameters,omitempty"`
+	Args     []Stmt       `json:"arguments,omitempty"`
+	Init     *Statement   `json:"init,omitempty"`
+	Left     *Statement   `json:"left,omitempty"`
+	Right    *Statement   `json:"right,omitempty"`
+	Block    *Block       `json:"block,omitempty"`
+	Cond     *Statement   `json:"condition,omitempty"`
+	Then     *Block       `json:"then,omitempty"`
+	Else     *Block       `json:"else,omitempty"`
+	Post     *Statement   `json:"post,omitempty"`
+	Expr     *Statement   `json:"expression,omitempty"`
+	Cases    []*Statement `json:"cases,omitempty"`
+}
+
+type StructDecl struct {
+	ID    int       `json:"id"`
+	Line  int       `json:"line"`
+	Type  string    `json:"type"`
+	Name  string    `json:"name"`
+	Attrs []VarDecl `json:"attributes"`
+}
+
+type ErrorNode struct {
+	ID    int    `json:"id"`
+	Line  int    `json:"line"`
+	Type  string `json:"type"`
+	Value string `json:"value"`
+	Desc  string `json:"description"`
+}
+
+// Decode parses a super-AST JSON document, as produced by AST.Visit, into a
+// *Block.
+func Decode(r io.Reader) (*Block, error) {
+	var b Block
+	if err := json.NewDecoder(r).Decode(&b); err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+func (b *Block) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		ID    int               `json:"id"`
+		Stmts []json.RawMessage `json:"statements"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	b.ID = raw.ID
+	b.Stmts = make([]Stmt, 0, len(raw.Stmts))
+	for _, rm := range raw.Stmts {
+		s, err := unmarshalStmt(rm)
+		if err != nil {
+			return err
+		}
+		b.Stmts = append(b.Stmts, s)
+	}
+	return nil
+}
+
+func (s *Statement) UnmarshalJSON(data []byte) error {
+	type alias Statement
+	var raw struct {
+		alias
+		Args []json.RawMessage `json:"arguments,omitempty"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*s = Statement(raw.alias)
+	for _, rm := range raw.Args {
+		a, err := unmarshalStmt(rm)
+		if err != nil {
+			return err
+		}
+		s.Args = append(s.Args, a)
+	}
+	return nil
+}
+
+func unmarshalStmt(data []byte) (Stmt, error) {
+	var head struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(data, &head); err != nil {
+		return nil, err
+	}
+	switch head.Type {
+	case "error":
+		var e ErrorNode
+		if err := json.Unmarshal(data, &e); err != nil {
+			return nil, err
+		}
+		return &e, nil
+	case "struct-declaration":
+		var d StructDecl
+		if err := json.Unmarshal(data, &d); err != nil {
+			return nil, err
+		}
+		return &d, nil
+	default:
+		var s Statement
+		if err := json.Unmarshal(data, &s); err != nil {
+			return nil, err
+		}
+		return &s, nil
+	}
+}
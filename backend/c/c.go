@@ -0,0 +1,208 @@
+// Package c implements backend.Backend, rendering a super-AST document as C.
+package c
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/mvdan/superast/backend"
+)
+
+// Backend emits C. The zero value is ready to use.
+type Backend struct{}
+
+func (Backend) Emit(w io.Writer, b *backend.Block) error {
+	iw := backend.NewIndentWriter("    ")
+	iw.Line("#include <stdio.h>")
+	iw.Line("")
+	for _, s := range b.Stmts {
+		renderStmt(iw, s)
+	}
+	_, err := w.Write(iw.Bytes())
+	return err
+}
+
+func renderStmt(w *backend.IndentWriter, s backend.Stmt) {
+	switch x := s.(type) {
+	case *backend.ErrorNode:
+		w.Line("/* unsupported %s: %s */", x.Desc, x.Value)
+	case *backend.StructDecl:
+		renderStructDecl(w, x)
+	case *backend.Statement:
+		renderStatement(w, x)
+	}
+}
+
+func renderStructDecl(w *backend.IndentWriter, d *backend.StructDecl) {
+	w.Line("typedef struct {")
+	w.Indent()
+	for _, a := range d.Attrs {
+		w.Line("%s %s;", typeString(a.DataType), a.Name)
+	}
+	w.Dedent()
+	w.Line("} %s;", backend.BareIdent(d.Name))
+}
+
+func newStyle() backend.ControlFlowStyle {
+	return backend.ControlFlowStyle{
+		RenderStmt: renderStmt,
+		RenderExpr: renderExpr,
+		SimpleText: simpleText,
+
+		If: "if (%s) {", ElseIf: "} else if (%s) {", Else: "} else {", EndIf: "}",
+		For: "for (%s; %s; %s) {", EndFor: "}",
+		Switch: "switch (%s) {", Case: "case %s:", Default: "default:", EndSwitch: "}",
+		CaseBreak: true,
+	}
+}
+
+func renderStatement(w *backend.IndentWriter, s *backend.Statement) {
+	switch s.Type {
+	case "function-declaration":
+		renderFuncDecl(w, s)
+	case "conditional":
+		backend.RenderIf(w, s, newStyle())
+	case "for":
+		backend.RenderFor(w, s, newStyle())
+	case "switch":
+		backend.RenderSwitch(w, s, newStyle())
+	case "type-switch":
+		w.Line("/* unsupported type-switch on %s: no runtime type info in this target */", renderExpr(s.Cond))
+	case "return":
+		if s.Expr != nil {
+			w.Line("return %s;", renderExpr(s.Expr))
+		} else {
+			w.Line("return;")
+		}
+	case "break", "continue":
+		w.Line("%s;", s.Type)
+	default:
+		w.Line("%s;", simpleText(s))
+	}
+}
+
+func renderFuncDecl(w *backend.IndentWriter, fn *backend.Statement) {
+	params := make([]string, len(fn.Params))
+	for i, p := range fn.Params {
+		params[i] = fmt.Sprintf("%s %s", typeString(p.DataType), p.Name)
+	}
+	w.Line("%s %s(%s) {", typeString(fn.RetType), fn.Name, strings.Join(params, ", "))
+	w.Indent()
+	backend.RenderBlock(w, fn.Block, newStyle())
+	w.Dedent()
+	w.Line("}")
+	w.Line("")
+}
+
+func simpleText(s *backend.Statement) string {
+	return backend.RenderSimpleText(s, backend.SimpleTextStyle{
+		RenderExpr:    renderExpr,
+		RenderVarDecl: renderVarDecl,
+		Assign: func(s *backend.Statement, rhs string) string {
+			return fmt.Sprintf("%s = %s", s.Name, rhs)
+		},
+		IncDec: func(s *backend.Statement) string {
+			return fmt.Sprintf("%s%s", renderExpr(s.Left), s.Type)
+		},
+		Compound: func(s *backend.Statement, rhs string) string {
+			return fmt.Sprintf("%s %s= %s", s.Name, s.Type, rhs)
+		},
+	})
+}
+
+func renderVarDecl(s *backend.Statement) string {
+	t := typeString(s.DataType)
+	init := ""
+	if backend.HasInit(s.Init) {
+		init = renderExpr(s.Init)
+	} else if s.DataType != nil {
+		init = backend.ZeroValues[s.DataType.Name]
+	}
+	if init == "" {
+		return fmt.Sprintf("%s %s", t, s.Name)
+	}
+	return fmt.Sprintf("%s %s = %s", t, s.Name, init)
+}
+
+func renderCall(s *backend.Statement) string {
+	if s.Name == "print" {
+		return renderPrintf(s.Args)
+	}
+	args := make([]string, len(s.Args))
+	for i, a := range s.Args {
+		as, _ := a.(*backend.Statement)
+		args[i] = renderExpr(as)
+	}
+	return fmt.Sprintf("%s(%s)", s.Name, strings.Join(args, ", "))
+}
+
+// renderPrintf turns a print(...) call into a printf with a format string
+// built from each argument's resolved type, since C has no varargs-safe
+// generic print of its own.
+func renderPrintf(argStmts []backend.Stmt) string {
+	specs := make([]string, len(argStmts))
+	args := make([]string, len(argStmts))
+	for i, a := range argStmts {
+		as, _ := a.(*backend.Statement)
+		specs[i] = formatSpec(as)
+		args[i] = renderExpr(as)
+	}
+	format := strconv.Quote(strings.Join(specs, " ") + "\n")
+	parts := append([]string{format}, args...)
+	return fmt.Sprintf("printf(%s)", strings.Join(parts, ", "))
+}
+
+func formatSpec(s *backend.Statement) string {
+	if s == nil {
+		return "%d"
+	}
+	t := s.Type
+	if s.DataType != nil {
+		t = s.DataType.Name
+	}
+	switch t {
+	case "string":
+		return "%s"
+	case "double", "float64":
+		return "%f"
+	case "char", "rune":
+		return "%c"
+	default:
+		return "%d"
+	}
+}
+
+func renderExpr(s *backend.Statement) string {
+	return backend.RenderExpr(s, "!", renderCall)
+}
+
+// typeString renders a dataType as a C type, falling back to "void*" for
+// maps, channels and interface{}, which have no natural C equivalent (C has
+// no map type). Any other name is used as-is, minus its package qualifier.
+func typeString(dt *backend.DataType) string {
+	if dt == nil {
+		return "void"
+	}
+	switch dt.Name {
+	case "void", "":
+		return "void"
+	case "double":
+		return "double"
+	case "char":
+		return "char"
+	case "string":
+		return "char*"
+	case "bool":
+		return "int"
+	case "slice", "array", "pointer":
+		return typeString(dt.SubType) + "*"
+	case "map", "channel":
+		return "void*"
+	case "interface{}":
+		return "void*"
+	default:
+		return backend.BareIdent(dt.Name)
+	}
+}
@@ -0,0 +1,232 @@
+package c
+
+import (
+	"bytes"
+	"encoding/json"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/mvdan/superast"
+	"github.com/mvdan/superast/backend"
+)
+
+// TestGolden decodes each testdata/*.json fixture and checks the emitted C
+// against the sibling .c file of the same name.
+func TestGolden(t *testing.T) {
+	entries, err := ioutil.ReadDir("testdata")
+	if err != nil {
+		t.Fatalf("failed reading testdata: %s", err)
+	}
+	for _, e := range entries {
+		if !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+		name := strings.TrimSuffix(e.Name(), ".json")
+		t.Run(name, func(t *testing.T) {
+			in, err := ioutil.ReadFile(path.Join("testdata", e.Name()))
+			if err != nil {
+				t.Fatalf("failed reading fixture: %s", err)
+			}
+			b, err := backend.Decode(bytes.NewReader(in))
+			if err != nil {
+				t.Fatalf("failed decoding fixture: %s", err)
+			}
+			var got bytes.Buffer
+			if err := (Backend{}).Emit(&got, b); err != nil {
+				t.Fatalf("Emit failed: %s", err)
+			}
+			want, err := ioutil.ReadFile(path.Join("testdata", name+".c"))
+			if err != nil {
+				t.Fatalf("failed reading golden file: %s", err)
+			}
+			if got.String() != string(want) {
+				t.Errorf("output mismatch\ngot:\n%s\nwant:\n%s", got.String(), want)
+			}
+		})
+	}
+}
+
+// TestTranslatedTypes runs real Go source through superast.NewAST and into
+// this backend, rather than a hand-authored JSON fixture - the
+// dataType.Name values resolveType derives from go/types (e.g. "double" for
+// float64, "char" for rune) only have to match what typeString here expects
+// if something actually exercises that full path.
+func TestTranslatedTypes(t *testing.T) {
+	const src = `package main
+
+func main() {
+	var f float64 = 3.14
+	var c rune = 'a'
+	_ = f
+	_ = c
+}
+`
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "in.go", src, 0)
+	if err != nil {
+		t.Fatalf("failed parsing source: %s", err)
+	}
+	a := superast.NewAST(fset, file)
+	ast.Walk(a, file)
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	if err := enc.Encode(a.RootBlock); err != nil {
+		t.Fatalf("failed encoding AST: %s", err)
+	}
+	b, err := backend.Decode(&buf)
+	if err != nil {
+		t.Fatalf("failed decoding AST: %s", err)
+	}
+	var got bytes.Buffer
+	if err := (Backend{}).Emit(&got, b); err != nil {
+		t.Fatalf("Emit failed: %s", err)
+	}
+	if !strings.Contains(got.String(), "double f = 3.14;") {
+		t.Errorf("expected a valid C double declaration, got:\n%s", got.String())
+	}
+	if !strings.Contains(got.String(), "char c = 'a';") {
+		t.Errorf("expected a valid C char declaration, got:\n%s", got.String())
+	}
+}
+
+// TestStructFieldSelector checks that accessing a struct field comes out as
+// a real field access, not a whole-struct copy: a *ast.SelectorExpr used to
+// fall through superast's Visit into a no-op passthrough, so "p.X" silently
+// lowered to "p".
+func TestStructFieldSelector(t *testing.T) {
+	const src = `package main
+
+type Point struct {
+	X int
+	Y int
+}
+
+func main() {
+	p := Point{X: 1, Y: 2}
+	println(p.X)
+}
+`
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "in.go", src, 0)
+	if err != nil {
+		t.Fatalf("failed parsing source: %s", err)
+	}
+	a := superast.NewAST(fset, file)
+	ast.Walk(a, file)
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	if err := enc.Encode(a.RootBlock); err != nil {
+		t.Fatalf("failed encoding AST: %s", err)
+	}
+	b, err := backend.Decode(&buf)
+	if err != nil {
+		t.Fatalf("failed decoding AST: %s", err)
+	}
+	var got bytes.Buffer
+	if err := (Backend{}).Emit(&got, b); err != nil {
+		t.Fatalf("Emit failed: %s", err)
+	}
+	if !strings.Contains(got.String(), "p.X") {
+		t.Errorf("expected the field access p.X to survive translation, got:\n%s", got.String())
+	}
+}
+
+// TestStructTypeNameConsistency checks that a struct's typedef name and a
+// variable declared with that struct type use the same identifier: the
+// struct-declaration's name used to be the bare name written at its
+// declaration, while a variable's dataType.Name came from go/types'
+// package-qualified form - two different spellings of the same type, one of
+// which (the raw qualified name) isn't even valid C syntax.
+func TestStructTypeNameConsistency(t *testing.T) {
+	const src = `package main
+
+type Point struct {
+	X int
+}
+
+func main() {
+	var p Point
+	_ = p
+}
+`
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "in.go", src, 0)
+	if err != nil {
+		t.Fatalf("failed parsing source: %s", err)
+	}
+	a := superast.NewAST(fset, file)
+	ast.Walk(a, file)
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	if err := enc.Encode(a.RootBlock); err != nil {
+		t.Fatalf("failed encoding AST: %s", err)
+	}
+	b, err := backend.Decode(&buf)
+	if err != nil {
+		t.Fatalf("failed decoding AST: %s", err)
+	}
+	var got bytes.Buffer
+	if err := (Backend{}).Emit(&got, b); err != nil {
+		t.Fatalf("Emit failed: %s", err)
+	}
+	if !strings.Contains(got.String(), "} Point;") {
+		t.Errorf("expected the struct typedef to be named Point, got:\n%s", got.String())
+	}
+	if !strings.Contains(got.String(), "Point p;") {
+		t.Errorf("expected p to be declared with the same Point type as the typedef, got:\n%s", got.String())
+	}
+}
+
+// TestTypeSwitchUnsupported checks that a Go type switch - which has no C
+// equivalent, C having no runtime type info to switch on - comes out as a
+// comment instead of the invalid "switch (x) { case int: ... }" C this
+// backend used to emit by treating it as a plain value switch.
+func TestTypeSwitchUnsupported(t *testing.T) {
+	const src = `package main
+
+func main() {
+	var x interface{} = 1
+	switch v := x.(type) {
+	case int:
+		println(v)
+	default:
+		println(0)
+	}
+}
+`
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "in.go", src, 0)
+	if err != nil {
+		t.Fatalf("failed parsing source: %s", err)
+	}
+	a := superast.NewAST(fset, file)
+	ast.Walk(a, file)
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	if err := enc.Encode(a.RootBlock); err != nil {
+		t.Fatalf("failed encoding AST: %s", err)
+	}
+	b, err := backend.Decode(&buf)
+	if err != nil {
+		t.Fatalf("failed decoding AST: %s", err)
+	}
+	var got bytes.Buffer
+	if err := (Backend{}).Emit(&got, b); err != nil {
+		t.Fatalf("Emit failed: %s", err)
+	}
+	if strings.Contains(got.String(), "switch (x)") || strings.Contains(got.String(), "case int:") {
+		t.Errorf("expected the type switch to be skipped, not mistranslated as a value switch:\n%s", got.String())
+	}
+	if !strings.Contains(got.String(), "/* unsupported type-switch") {
+		t.Errorf("expected an unsupported-construct comment, got:\n%s", got.String())
+	}
+}
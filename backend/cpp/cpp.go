@@ -0,0 +1,217 @@
+// Package cpp implements backend.Backend, rendering a super-AST document as
+// C++.
+package cpp
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/mvdan/superast/backend"
+)
+
+// Backend emits C++. The zero value is ready to use.
+type Backend struct{}
+
+func (Backend) Emit(w io.Writer, b *backend.Block) error {
+	iw := backend.NewIndentWriter("    ")
+	iw.Line("#include <iostream>")
+	iw.Line("")
+	for _, s := range b.Stmts {
+		renderStmt(iw, s)
+	}
+	_, err := w.Write(iw.Bytes())
+	return err
+}
+
+func renderStmt(w *backend.IndentWriter, s backend.Stmt) {
+	switch x := s.(type) {
+	case *backend.ErrorNode:
+		w.Line("/* unsupported %s: %s */", x.Desc, x.Value)
+	case *backend.StructDecl:
+		renderStructDecl(w, x)
+	case *backend.Statement:
+		renderStatement(w, x)
+	}
+}
+
+// renderStructDecl emits a struct with its fields default- or
+// value-initialised in a member initialiser list, where the C backend emits
+// a plain typedef struct.
+func renderStructDecl(w *backend.IndentWriter, d *backend.StructDecl) {
+	w.Line("struct %s {", backend.BareIdent(d.Name))
+	w.Indent()
+	for _, a := range d.Attrs {
+		w.Line("%s %s;", typeString(a.DataType), a.Name)
+	}
+	if len(d.Attrs) > 0 {
+		names := make([]string, len(d.Attrs))
+		inits := make([]string, len(d.Attrs))
+		for i, a := range d.Attrs {
+			names[i] = a.Name
+			inits[i] = zeroValue(a.DataType)
+		}
+		w.Line("%s() : %s {}", backend.BareIdent(d.Name), strings.Join(initList(names, inits), ", "))
+	}
+	w.Dedent()
+	w.Line("};")
+}
+
+func initList(names, inits []string) []string {
+	out := make([]string, len(names))
+	for i := range names {
+		out[i] = fmt.Sprintf("%s(%s)", names[i], inits[i])
+	}
+	return out
+}
+
+func newStyle() backend.ControlFlowStyle {
+	return backend.ControlFlowStyle{
+		RenderStmt: renderStmt,
+		RenderExpr: renderExpr,
+		SimpleText: simpleText,
+
+		If: "if (%s) {", ElseIf: "} else if (%s) {", Else: "} else {", EndIf: "}",
+		For: "for (%s; %s; %s) {", EndFor: "}",
+		Switch: "switch (%s) {", Case: "case %s:", Default: "default:", EndSwitch: "}",
+		CaseBreak: true,
+	}
+}
+
+func renderStatement(w *backend.IndentWriter, s *backend.Statement) {
+	switch s.Type {
+	case "function-declaration":
+		renderFuncDecl(w, s)
+	case "conditional":
+		backend.RenderIf(w, s, newStyle())
+	case "for":
+		backend.RenderFor(w, s, newStyle())
+	case "switch":
+		backend.RenderSwitch(w, s, newStyle())
+	case "type-switch":
+		w.Line("/* unsupported type-switch on %s: no RTTI-based dispatch in this target */", renderExpr(s.Cond))
+	case "return":
+		if s.Expr != nil {
+			w.Line("return %s;", renderExpr(s.Expr))
+		} else {
+			w.Line("return;")
+		}
+	case "break", "continue":
+		w.Line("%s;", s.Type)
+	default:
+		w.Line("%s;", simpleText(s))
+	}
+}
+
+func renderFuncDecl(w *backend.IndentWriter, fn *backend.Statement) {
+	params := make([]string, len(fn.Params))
+	for i, p := range fn.Params {
+		params[i] = fmt.Sprintf("%s %s", typeString(p.DataType), p.Name)
+	}
+	w.Line("%s %s(%s) {", typeString(fn.RetType), fn.Name, strings.Join(params, ", "))
+	w.Indent()
+	backend.RenderBlock(w, fn.Block, newStyle())
+	w.Dedent()
+	w.Line("}")
+	w.Line("")
+}
+
+func zeroValue(dt *backend.DataType) string {
+	if dt == nil {
+		return "0"
+	}
+	if v, ok := backend.ZeroValues[dt.Name]; ok {
+		return v
+	}
+	return "0"
+}
+
+func simpleText(s *backend.Statement) string {
+	return backend.RenderSimpleText(s, backend.SimpleTextStyle{
+		RenderExpr:    renderExpr,
+		RenderVarDecl: renderVarDecl,
+		Assign: func(s *backend.Statement, rhs string) string {
+			return fmt.Sprintf("%s = %s", s.Name, rhs)
+		},
+		IncDec: func(s *backend.Statement) string {
+			return fmt.Sprintf("%s%s", renderExpr(s.Left), s.Type)
+		},
+		Compound: func(s *backend.Statement, rhs string) string {
+			return fmt.Sprintf("%s %s= %s", s.Name, s.Type, rhs)
+		},
+	})
+}
+
+func renderVarDecl(s *backend.Statement) string {
+	t := typeString(s.DataType)
+	init := ""
+	if backend.HasInit(s.Init) {
+		init = renderExpr(s.Init)
+	} else if s.DataType != nil {
+		init = zeroValue(s.DataType)
+	}
+	if init == "" {
+		return fmt.Sprintf("%s %s", t, s.Name)
+	}
+	return fmt.Sprintf("%s %s = %s", t, s.Name, init)
+}
+
+func renderCall(s *backend.Statement) string {
+	if s.Name == "print" {
+		return renderCout(s.Args)
+	}
+	args := make([]string, len(s.Args))
+	for i, a := range s.Args {
+		as, _ := a.(*backend.Statement)
+		args[i] = renderExpr(as)
+	}
+	return fmt.Sprintf("%s(%s)", s.Name, strings.Join(args, ", "))
+}
+
+// renderCout turns a print(...) call into a chain of std::cout <<
+// insertions, one per argument, space-separated and newline-terminated.
+func renderCout(argStmts []backend.Stmt) string {
+	parts := make([]string, len(argStmts))
+	for i, a := range argStmts {
+		as, _ := a.(*backend.Statement)
+		parts[i] = renderExpr(as)
+	}
+	chain := strings.Join(parts, ` << " " << `)
+	return fmt.Sprintf(`std::cout << %s << std::endl`, chain)
+}
+
+func renderExpr(s *backend.Statement) string {
+	return backend.RenderExpr(s, "!", renderCall)
+}
+
+// typeString renders a dataType as a C++ type, preferring std:: containers
+// over C's raw pointers where there's a natural fit.
+func typeString(dt *backend.DataType) string {
+	if dt == nil {
+		return "void"
+	}
+	switch dt.Name {
+	case "void", "":
+		return "void"
+	case "double":
+		return "double"
+	case "char":
+		return "char"
+	case "string":
+		return "std::string"
+	case "bool":
+		return "bool"
+	case "slice", "array":
+		return "std::vector<" + typeString(dt.SubType) + ">"
+	case "pointer":
+		return typeString(dt.SubType) + "*"
+	case "map":
+		return "std::map<int, " + typeString(dt.SubType) + ">"
+	case "channel":
+		return "void*"
+	case "interface{}":
+		return "void*"
+	default:
+		return backend.BareIdent(dt.Name)
+	}
+}
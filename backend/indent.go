@@ -0,0 +1,42 @@
+package backend
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// IndentWriter accumulates lines at a given nesting depth. Backends that
+// target a language without an equivalent of go/format.Source (C, C++,
+// pseudocode) need to track indentation themselves as they walk the tree;
+// this is the bit of bookkeeping they'd otherwise each reimplement.
+type IndentWriter struct {
+	buf    bytes.Buffer
+	depth  int
+	indent string
+}
+
+// NewIndentWriter creates an IndentWriter that repeats indent once per
+// nesting level.
+func NewIndentWriter(indent string) *IndentWriter {
+	return &IndentWriter{indent: indent}
+}
+
+func (w *IndentWriter) Indent() { w.depth++ }
+
+func (w *IndentWriter) Dedent() {
+	if w.depth > 0 {
+		w.depth--
+	}
+}
+
+// Line writes one line at the current depth, formatted per fmt.Sprintf.
+func (w *IndentWriter) Line(format string, args ...interface{}) {
+	w.buf.WriteString(strings.Repeat(w.indent, w.depth))
+	fmt.Fprintf(&w.buf, format, args...)
+	w.buf.WriteByte('\n')
+}
+
+func (w *IndentWriter) Bytes() []byte {
+	return w.buf.Bytes()
+}
@@ -0,0 +1,178 @@
+// Package pseudocode implements backend.Backend, rendering a super-AST
+// document as plain-English structured pseudocode, intended for teaching
+// rather than execution.
+package pseudocode
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/mvdan/superast/backend"
+)
+
+// Backend emits pseudocode. The zero value is ready to use.
+type Backend struct{}
+
+func (Backend) Emit(w io.Writer, b *backend.Block) error {
+	iw := backend.NewIndentWriter("    ")
+	for _, s := range b.Stmts {
+		renderStmt(iw, s)
+	}
+	_, err := w.Write(iw.Bytes())
+	return err
+}
+
+func renderStmt(w *backend.IndentWriter, s backend.Stmt) {
+	switch x := s.(type) {
+	case *backend.ErrorNode:
+		w.Line("// unsupported %s: %s", x.Desc, x.Value)
+	case *backend.StructDecl:
+		renderStructDecl(w, x)
+	case *backend.Statement:
+		renderStatement(w, x)
+	}
+}
+
+func renderStructDecl(w *backend.IndentWriter, d *backend.StructDecl) {
+	w.Line("STRUCT %s", d.Name)
+	w.Indent()
+	for _, a := range d.Attrs {
+		w.Line("%s: %s", a.Name, typeString(a.DataType))
+	}
+	w.Dedent()
+	w.Line("END STRUCT")
+}
+
+func newStyle() backend.ControlFlowStyle {
+	return backend.ControlFlowStyle{
+		RenderStmt: renderStmt,
+		RenderExpr: renderExpr,
+		SimpleText: simpleText,
+
+		If: "IF %s THEN", ElseIf: "ELSE IF %s THEN", Else: "ELSE", EndIf: "END IF",
+		For: "FOR %s; %s; %s", EndFor: "END FOR",
+		Switch: "SWITCH %s", Case: "CASE %s:", Default: "DEFAULT:", EndSwitch: "END SWITCH",
+	}
+}
+
+func renderStatement(w *backend.IndentWriter, s *backend.Statement) {
+	switch s.Type {
+	case "function-declaration":
+		renderFuncDecl(w, s)
+	case "conditional":
+		backend.RenderIf(w, s, newStyle())
+	case "for":
+		backend.RenderFor(w, s, newStyle())
+	case "switch":
+		backend.RenderSwitch(w, s, newStyle())
+	case "type-switch":
+		w.Line("// unsupported type-switch on %s: no runtime type info in this target", renderExpr(s.Cond))
+	case "return":
+		if s.Expr != nil {
+			w.Line("RETURN %s", renderExpr(s.Expr))
+		} else {
+			w.Line("RETURN")
+		}
+	case "break", "continue":
+		w.Line(strings.ToUpper(s.Type))
+	default:
+		w.Line("%s", simpleText(s))
+	}
+}
+
+func renderFuncDecl(w *backend.IndentWriter, fn *backend.Statement) {
+	params := make([]string, len(fn.Params))
+	for i, p := range fn.Params {
+		params[i] = fmt.Sprintf("%s: %s", p.Name, typeString(p.DataType))
+	}
+	w.Line("FUNCTION %s(%s)", fn.Name, strings.Join(params, ", "))
+	w.Indent()
+	backend.RenderBlock(w, fn.Block, newStyle())
+	w.Dedent()
+	w.Line("END FUNCTION")
+	w.Line("")
+}
+
+func simpleText(s *backend.Statement) string {
+	return backend.RenderSimpleText(s, backend.SimpleTextStyle{
+		RenderExpr:    renderExpr,
+		RenderVarDecl: renderVarDecl,
+		Assign: func(s *backend.Statement, rhs string) string {
+			return fmt.Sprintf("SET %s TO %s", s.Name, rhs)
+		},
+		IncDec: func(s *backend.Statement) string {
+			verb := "INCREMENT"
+			if s.Type == "--" {
+				verb = "DECREMENT"
+			}
+			return fmt.Sprintf("%s %s", verb, renderExpr(s.Left))
+		},
+		Compound: func(s *backend.Statement, rhs string) string {
+			return fmt.Sprintf("SET %s TO %s %s %s", s.Name, s.Name, s.Type, rhs)
+		},
+	})
+}
+
+func renderVarDecl(s *backend.Statement) string {
+	t := typeString(s.DataType)
+	if backend.HasInit(s.Init) {
+		return fmt.Sprintf("DECLARE %s: %s = %s", s.Name, t, renderExpr(s.Init))
+	}
+	return fmt.Sprintf("DECLARE %s: %s", s.Name, t)
+}
+
+func renderCall(s *backend.Statement) string {
+	if s.Name == "print" {
+		return renderPrint(s.Args)
+	}
+	args := make([]string, len(s.Args))
+	for i, a := range s.Args {
+		as, _ := a.(*backend.Statement)
+		args[i] = renderExpr(as)
+	}
+	return fmt.Sprintf("%s(%s)", s.Name, strings.Join(args, ", "))
+}
+
+func renderPrint(argStmts []backend.Stmt) string {
+	parts := make([]string, len(argStmts))
+	for i, a := range argStmts {
+		as, _ := a.(*backend.Statement)
+		parts[i] = renderExpr(as)
+	}
+	return fmt.Sprintf("PRINT %s", strings.Join(parts, ", "))
+}
+
+func renderExpr(s *backend.Statement) string {
+	return backend.RenderExpr(s, "NOT ", renderCall)
+}
+
+func typeString(dt *backend.DataType) string {
+	if dt == nil {
+		return "Any"
+	}
+	switch dt.Name {
+	case "void", "":
+		return "Void"
+	case "double":
+		return "Number"
+	case "char":
+		return "Character"
+	case "string":
+		return "String"
+	case "bool":
+		return "Boolean"
+	case "int":
+		return "Integer"
+	case "slice", "array":
+		return "List of " + typeString(dt.SubType)
+	case "map":
+		return "Map of " + typeString(dt.SubType)
+	case "pointer":
+		return "Pointer to " + typeString(dt.SubType)
+	case "channel":
+		return "Channel of " + typeString(dt.SubType)
+	default:
+		return dt.Name
+	}
+}
@@ -0,0 +1,47 @@
+package pseudocode
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/mvdan/superast/backend"
+)
+
+// TestGolden decodes each testdata/*.json fixture and checks the emitted
+// pseudocode against the sibling .pseudo file of the same name.
+func TestGolden(t *testing.T) {
+	entries, err := ioutil.ReadDir("testdata")
+	if err != nil {
+		t.Fatalf("failed reading testdata: %s", err)
+	}
+	for _, e := range entries {
+		if !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+		name := strings.TrimSuffix(e.Name(), ".json")
+		t.Run(name, func(t *testing.T) {
+			in, err := ioutil.ReadFile(path.Join("testdata", e.Name()))
+			if err != nil {
+				t.Fatalf("failed reading fixture: %s", err)
+			}
+			b, err := backend.Decode(bytes.NewReader(in))
+			if err != nil {
+				t.Fatalf("failed decoding fixture: %s", err)
+			}
+			var got bytes.Buffer
+			if err := (Backend{}).Emit(&got, b); err != nil {
+				t.Fatalf("Emit failed: %s", err)
+			}
+			want, err := ioutil.ReadFile(path.Join("testdata", name+".pseudo"))
+			if err != nil {
+				t.Fatalf("failed reading golden file: %s", err)
+			}
+			if got.String() != string(want) {
+				t.Errorf("output mismatch\ngot:\n%s\nwant:\n%s", got.String(), want)
+			}
+		})
+	}
+}
@@ -0,0 +1,271 @@
+package backend
+
+import "strconv"
+
+// CompoundOps maps the node type assignType gives a compound assignment
+// ("+", "-", ...) back to whether it's one of Go's compound-assignment
+// operators, shared by every backend so RenderSimpleText can tell a compound
+// assignment apart from a bare expression; each backend then phrases it
+// through its own SimpleTextStyle.Compound.
+var CompoundOps = map[string]bool{
+	"+": true, "-": true, "*": true, "/": true, "%": true,
+	"&": true, "|": true, "^": true, "<<": true, ">>": true, "&^": true,
+}
+
+// ZeroValues mirrors superast's own table of per-type zero-value literals,
+// shared here since a backend only ever sees the decoded JSON tree, never
+// superast's internals.
+var ZeroValues = map[string]string{
+	"int":    "0",
+	"double": "0.0",
+	"char":   "'\\0'",
+	"string": `""`,
+}
+
+// UnaryOp turns a unary node's value ("pos", "neg", "not", "addr") into the
+// operator a backend should print before its operand. not is the backend's
+// own spelling of logical negation, the one operator that isn't a shared
+// symbol across every target (C and C++ use "!", pseudocode spells it out as
+// "NOT ").
+func UnaryOp(v, not string) string {
+	switch v {
+	case "pos":
+		return "+"
+	case "neg":
+		return "-"
+	case "not":
+		return not
+	case "addr":
+		return "&"
+	}
+	return v
+}
+
+// IsNumericLit reports whether v reads as a Go integer or floating-point
+// literal, as opposed to the decoded text of a rune or string literal.
+func IsNumericLit(v string) bool {
+	if _, err := strconv.ParseInt(v, 0, 64); err == nil {
+		return true
+	}
+	if _, err := strconv.ParseUint(v, 0, 64); err == nil {
+		return true
+	}
+	_, err := strconv.ParseFloat(v, 64)
+	return err == nil
+}
+
+// IsElseIf reports whether an "else" block is really an "else if": the
+// single-statement case Visit produces when it walks an *ast.IfStmt's
+// x.Else that is itself an *ast.IfStmt rather than a block.
+func IsElseIf(b *Block) bool {
+	if b == nil || len(b.Stmts) != 1 {
+		return false
+	}
+	s, ok := b.Stmts[0].(*Statement)
+	return ok && s.Type == "conditional"
+}
+
+// HasInit reports whether init is an actual initializer rather than the
+// empty placeholder statement (Type set, everything else zero) a
+// variable-declaration with no value and no known zero value decodes to.
+func HasInit(init *Statement) bool {
+	return init != nil && (init.Value != "" || init.Left != nil || init.Right != nil || init.Expr != nil || init.Args != nil)
+}
+
+// BareIdent strips a go/types package qualifier (e.g. "main.Point" ->
+// "Point") from a dataType name, which isn't valid syntax in C or C++ on its
+// own, but matches the same named type's struct-declaration one-for-one, so
+// stripping the qualifier here keeps a typedef/struct and the variables
+// declared with it using the same name.
+func BareIdent(name string) string {
+	for i := len(name) - 1; i >= 0; i-- {
+		if name[i] == '.' {
+			return name[i+1:]
+		}
+	}
+	return name
+}
+
+// RenderExpr renders an expression node the way every backend agrees on:
+// identifiers, literals, binary/unary ops, indexing, selectors and calls.
+// not is the backend's spelling of unary logical negation (see UnaryOp);
+// call renders a function-call node, the one case with real per-backend
+// behavior (printf, std::cout, PRINT, ...).
+func RenderExpr(s *Statement, not string, call func(*Statement) string) string {
+	if s == nil {
+		return ""
+	}
+	switch s.Type {
+	case "identifier":
+		return s.Value
+	case "string":
+		return strconv.Quote(s.Value)
+	case "char":
+		if rs := []rune(s.Value); len(rs) == 1 {
+			return strconv.QuoteRune(rs[0])
+		}
+		return strconv.Quote(s.Value)
+	case "binary":
+		return "(" + RenderExpr(s.Left, not, call) + " " + s.Value + " " + RenderExpr(s.Right, not, call) + ")"
+	case "unary":
+		return "(" + UnaryOp(s.Value, not) + RenderExpr(s.Expr, not, call) + ")"
+	case "index":
+		return RenderExpr(s.Left, not, call) + "[" + RenderExpr(s.Right, not, call) + "]"
+	case "selector":
+		return RenderExpr(s.Left, not, call) + "." + s.Name
+	case "function-call":
+		return call(s)
+	default:
+		if IsNumericLit(s.Value) || s.Value == "true" || s.Value == "false" {
+			return s.Value
+		}
+		if rs := []rune(s.Value); len(rs) == 1 {
+			return strconv.QuoteRune(rs[0])
+		}
+		return strconv.Quote(s.Value)
+	}
+}
+
+// SimpleTextStyle supplies the per-backend formatting RenderSimpleText needs
+// for the one case (variable-declaration, assignment, ++/--, or a compound
+// assignment) it can't phrase the same way across every target.
+type SimpleTextStyle struct {
+	RenderExpr    func(*Statement) string
+	RenderVarDecl func(*Statement) string
+	Assign        func(s *Statement, rhs string) string
+	IncDec        func(s *Statement) string
+	Compound      func(s *Statement, rhs string) string
+}
+
+// RenderSimpleText renders a variable-declaration, assignment, ++/-- or bare
+// expression statement, dispatching each case to style so the C, C++ and
+// pseudocode backends don't each reimplement the same switch.
+func RenderSimpleText(s *Statement, style SimpleTextStyle) string {
+	if s == nil {
+		return ""
+	}
+	switch s.Type {
+	case "variable-declaration":
+		return style.RenderVarDecl(s)
+	case "assignment":
+		return style.Assign(s, style.RenderExpr(s.Init))
+	case "++", "--":
+		return style.IncDec(s)
+	default:
+		if CompoundOps[s.Type] {
+			return style.Compound(s, style.RenderExpr(s.Init))
+		}
+		return style.RenderExpr(s)
+	}
+}
+
+// ControlFlowStyle groups the keywords/format strings and callbacks a
+// backend's if/for/switch rendering needs, so RenderBlock, RenderIf,
+// RenderFor and RenderSwitch can stay backend-agnostic. Every format string
+// takes already-rendered strings (a condition, an init clause, ...), never a
+// *Statement.
+type ControlFlowStyle struct {
+	RenderStmt func(w *IndentWriter, s Stmt)
+	RenderExpr func(*Statement) string
+	SimpleText func(*Statement) string
+
+	If, ElseIf, Else, EndIf string // If/ElseIf take the condition; Else/EndIf take no args
+	For, EndFor             string // For takes init, cond, post
+	Switch                  string // takes the switch's condition
+	Case, Default           string // Case takes one case value
+	EndSwitch               string
+	CaseBreak               bool // true if each case should end with an explicit "break;"
+}
+
+// RenderBlock renders every statement in b, or does nothing for a nil block
+// (an omitted "else", an empty case body, ...).
+func RenderBlock(w *IndentWriter, b *Block, style ControlFlowStyle) {
+	if b == nil {
+		return
+	}
+	for _, s := range b.Stmts {
+		style.RenderStmt(w, s)
+	}
+}
+
+// RenderIf renders an "if" statement and its "then" block, then hands the
+// "else" branch to RenderElse.
+func RenderIf(w *IndentWriter, s *Statement, style ControlFlowStyle) {
+	w.Line(style.If, style.RenderExpr(s.Cond))
+	w.Indent()
+	RenderBlock(w, s.Then, style)
+	w.Dedent()
+	RenderElse(w, s.Else, style)
+}
+
+// RenderElse closes the preceding block and, for a chain of else-ifs,
+// recurses so each link prints as a single "else if" line rather than a
+// nested block.
+func RenderElse(w *IndentWriter, elseBlock *Block, style ControlFlowStyle) {
+	if elseBlock == nil {
+		w.Line(style.EndIf)
+		return
+	}
+	if IsElseIf(elseBlock) {
+		nested := elseBlock.Stmts[0].(*Statement)
+		w.Line(style.ElseIf, style.RenderExpr(nested.Cond))
+		w.Indent()
+		RenderBlock(w, nested.Then, style)
+		w.Dedent()
+		RenderElse(w, nested.Else, style)
+		return
+	}
+	w.Line(style.Else)
+	w.Indent()
+	RenderBlock(w, elseBlock, style)
+	w.Dedent()
+	w.Line(style.EndIf)
+}
+
+// RenderFor renders a "for" statement's init/cond/post clause and body.
+func RenderFor(w *IndentWriter, s *Statement, style ControlFlowStyle) {
+	init, cond, post := "", "", ""
+	if s.Init != nil {
+		init = style.SimpleText(s.Init)
+	}
+	if s.Cond != nil {
+		cond = style.RenderExpr(s.Cond)
+	}
+	if s.Post != nil {
+		post = style.SimpleText(s.Post)
+	}
+	w.Line(style.For, init, cond, post)
+	w.Indent()
+	RenderBlock(w, s.Block, style)
+	w.Dedent()
+	w.Line(style.EndFor)
+}
+
+// RenderSwitch renders a value switch's cases, optionally closing each one
+// with an explicit "break;" per style.CaseBreak.
+func RenderSwitch(w *IndentWriter, s *Statement, style ControlFlowStyle) {
+	cond := ""
+	if s.Cond != nil {
+		cond = style.RenderExpr(s.Cond)
+	}
+	w.Line(style.Switch, cond)
+	w.Indent()
+	for _, c := range s.Cases {
+		if len(c.Args) == 0 {
+			w.Line(style.Default)
+		} else {
+			for _, arg := range c.Args {
+				as, _ := arg.(*Statement)
+				w.Line(style.Case, style.RenderExpr(as))
+			}
+		}
+		w.Indent()
+		RenderBlock(w, c.Block, style)
+		if style.CaseBreak {
+			w.Line("break;")
+		}
+		w.Dedent()
+	}
+	w.Dedent()
+	w.Line(style.EndSwitch)
+}
@@ -0,0 +1,23 @@
+package main
+
+import (
+	"encoding/json"
+	"go/token"
+	"log"
+	"os"
+
+	"github.com/mvdan/superast"
+)
+
+// gotranslate-render is the inverse of AST.Visit: it reads a super-AST
+// JSON document from stdin and writes the gofmt-clean Go source it
+// represents to stdout.
+func main() {
+	a := superast.NewAST(token.NewFileSet())
+	if err := json.NewDecoder(os.Stdin).Decode(a.RootBlock); err != nil {
+		log.Fatalf("Could not decode json: %v", err)
+	}
+	if err := superast.Render(os.Stdout, a.RootBlock); err != nil {
+		log.Fatalf("Could not render source: %v", err)
+	}
+}
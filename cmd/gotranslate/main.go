@@ -0,0 +1,137 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+
+	"github.com/mvdan/superast"
+	"github.com/mvdan/superast/backend"
+	"github.com/mvdan/superast/backend/c"
+	"github.com/mvdan/superast/backend/cpp"
+	"github.com/mvdan/superast/backend/pseudocode"
+)
+
+var (
+	target = flag.String("target", "go", "output target: go, c, cpp or pseudo")
+	mode   = flag.String("mode", "full", "parse mode: full, header or exported")
+)
+
+// gotranslate builds the super-AST for one or more Go sources and writes the
+// translated source for -target to stdout, one program per input in order.
+// With no arguments it reads a single program from stdin, as before; given a
+// single argument naming a directory, it translates every file in that
+// package; otherwise each argument is translated as a path to a file.
+func main() {
+	flag.Parse()
+
+	pm, err := parseMode(*mode)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	asts, err := translateArgs(flag.Args(), pm)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for _, a := range asts {
+		if err := emit(a); err != nil {
+			log.Fatalf("Could not render source: %v", err)
+		}
+	}
+}
+
+// translateArgs resolves args into super-ASTs: no args reads a single
+// program from stdin, one arg naming a directory translates that package,
+// and anything else is translated file by file via a shared Translator so
+// repeated paths come back from cache instead of being re-parsed.
+func translateArgs(args []string, pm superast.ParseMode) ([]*superast.AST, error) {
+	if len(args) == 0 {
+		a, err := translateStdin(pm)
+		if err != nil {
+			return nil, err
+		}
+		return []*superast.AST{a}, nil
+	}
+
+	tr := superast.NewTranslator(pm)
+	if len(args) == 1 {
+		if fi, err := os.Stat(args[0]); err == nil && fi.IsDir() {
+			return tr.TranslateDir(args[0])
+		}
+	}
+	asts := make([]*superast.AST, len(args))
+	for i, path := range args {
+		a, err := tr.TranslateFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("translate %s: %w", path, err)
+		}
+		asts[i] = a
+	}
+	return asts, nil
+}
+
+func translateStdin(pm superast.ParseMode) (*superast.AST, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "stdin.go", os.Stdin, 0)
+	if err != nil {
+		return nil, err
+	}
+	a := superast.NewAST(fset, f)
+	a.Mode = pm
+	ast.Walk(a, f)
+	return a, nil
+}
+
+func parseMode(s string) (superast.ParseMode, error) {
+	switch s {
+	case "full":
+		return superast.ParseFull, nil
+	case "header":
+		return superast.ParseHeader, nil
+	case "exported":
+		return superast.ParseExported, nil
+	default:
+		return 0, fmt.Errorf("unknown mode %q", s)
+	}
+}
+
+func emit(a *superast.AST) error {
+	if *target == "go" {
+		return superast.Render(os.Stdout, a.RootBlock)
+	}
+
+	be, err := backendFor(*target)
+	if err != nil {
+		return err
+	}
+	raw, err := json.Marshal(a.RootBlock)
+	if err != nil {
+		return fmt.Errorf("could not generate json: %w", err)
+	}
+	b, err := backend.Decode(bytes.NewReader(raw))
+	if err != nil {
+		return fmt.Errorf("could not decode json: %w", err)
+	}
+	return be.Emit(os.Stdout, b)
+}
+
+func backendFor(target string) (backend.Backend, error) {
+	switch target {
+	case "c":
+		return c.Backend{}, nil
+	case "cpp":
+		return cpp.Backend{}, nil
+	case "pseudo":
+		return pseudocode.Backend{}, nil
+	default:
+		return nil, fmt.Errorf("unknown target %q", target)
+	}
+}
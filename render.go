@@ -0,0 +1,459 @@
+package superast
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/mvdan/superast/backend"
+)
+
+// Render walks b and writes the Go source it represents to w, gofmt'd via
+// go/format.Source. It is the inverse of AST.Visit: given the in-memory (or
+// JSON-decoded) super-AST, it reconstructs a program that produces it.
+//
+// Render doesn't try to recover the original import list; calls use
+// whatever name is in the "name" field (println instead of fmt.Println, the
+// reverse of the mapping Visit applies), so the generated package never
+// needs one. Anything it can't express, namely an errorNode, is emitted as
+// a comment so the rest of the program still renders.
+func Render(w io.Writer, b *block) error {
+	var buf bytes.Buffer
+	buf.WriteString("package main\n")
+	for _, s := range b.Stmts {
+		renderStmt(&buf, s)
+	}
+	out, err := format.Source(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("superast: render: %s", err)
+	}
+	_, err = w.Write(out)
+	return err
+}
+
+func renderStmt(buf *bytes.Buffer, s stmt) {
+	switch x := s.(type) {
+	case *errorNode:
+		fmt.Fprintf(buf, "/* unsupported %s: %s */\n", x.Desc, x.Value)
+	case *structDecl:
+		renderStructDecl(buf, x)
+	case *statement:
+		renderStatement(buf, x)
+	}
+}
+
+func renderStructDecl(buf *bytes.Buffer, d *structDecl) {
+	fmt.Fprintf(buf, "\ntype %s struct {\n", strings.TrimPrefix(d.Name, "main."))
+	for _, a := range d.Attrs {
+		fmt.Fprintf(buf, "%s %s\n", a.Name, typeString(a.DataType))
+	}
+	buf.WriteString("}\n")
+}
+
+func renderStatement(buf *bytes.Buffer, s *statement) {
+	switch s.Type {
+	case "function-declaration":
+		renderFuncDecl(buf, s)
+	case "conditional":
+		renderIf(buf, s)
+	case "for":
+		renderFor(buf, s)
+	case "switch":
+		renderSwitch(buf, s)
+	case "type-switch":
+		renderTypeSwitch(buf, s)
+	case "return":
+		if s.Expr != nil {
+			fmt.Fprintf(buf, "return %s\n", renderExpr(s.Expr))
+		} else {
+			buf.WriteString("return\n")
+		}
+	case "break", "continue":
+		fmt.Fprintf(buf, "%s\n", s.Type)
+	default:
+		buf.WriteString(renderSimpleText(s) + "\n")
+	}
+}
+
+func renderFuncDecl(buf *bytes.Buffer, fn *statement) {
+	fmt.Fprintf(buf, "\nfunc %s(", fn.Name)
+	for i, p := range fn.Params {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		fmt.Fprintf(buf, "%s %s", p.Name, typeString(p.DataType))
+	}
+	buf.WriteString(") ")
+	if ret := typeString(fn.RetType); ret != "" {
+		buf.WriteString(ret + " ")
+	}
+	buf.WriteString("{\n")
+	renderBlock(buf, fn.Block)
+	buf.WriteString("}\n")
+}
+
+func renderBlock(buf *bytes.Buffer, b *block) {
+	if b == nil {
+		return
+	}
+	for _, s := range b.Stmts {
+		renderStmt(buf, s)
+	}
+}
+
+func renderIf(buf *bytes.Buffer, s *statement) {
+	buf.WriteString("if ")
+	buf.WriteString(renderExpr(s.Cond))
+	buf.WriteString(" {\n")
+	renderBlock(buf, s.Then)
+	buf.WriteString("}")
+	switch {
+	case s.Else == nil:
+		buf.WriteString("\n")
+	case isElseIf(s.Else):
+		buf.WriteString(" else ")
+		renderIf(buf, s.Else.Stmts[0].(*statement))
+	default:
+		buf.WriteString(" else {\n")
+		renderBlock(buf, s.Else)
+		buf.WriteString("}\n")
+	}
+}
+
+// isElseIf reports whether an "else" block is really an "else if": the
+// single-statement case Visit produces when it walks an *ast.IfStmt's
+// x.Else that is itself an *ast.IfStmt rather than a block.
+func isElseIf(b *block) bool {
+	if len(b.Stmts) != 1 {
+		return false
+	}
+	s, ok := b.Stmts[0].(*statement)
+	return ok && s.Type == "conditional"
+}
+
+func renderFor(buf *bytes.Buffer, s *statement) {
+	buf.WriteString("for ")
+	switch {
+	case s.Init == nil && s.Post == nil && s.Cond == nil:
+		// infinite loop: nothing before "{"
+	case s.Init == nil && s.Post == nil:
+		buf.WriteString(renderExpr(s.Cond) + " ")
+	default:
+		buf.WriteString(renderSimpleText(s.Init) + "; " + renderExpr(s.Cond) + "; " + renderSimpleText(s.Post) + " ")
+	}
+	buf.WriteString("{\n")
+	renderBlock(buf, s.Block)
+	buf.WriteString("}\n")
+}
+
+func renderSwitch(buf *bytes.Buffer, s *statement) {
+	buf.WriteString("switch ")
+	if s.Init != nil {
+		buf.WriteString(renderSimpleText(s.Init) + "; ")
+	}
+	if s.Cond != nil {
+		buf.WriteString(renderExpr(s.Cond) + " ")
+	}
+	buf.WriteString("{\n")
+	for _, c := range s.Cases {
+		if len(c.Args) == 0 {
+			buf.WriteString("default:\n")
+		} else {
+			parts := make([]string, len(c.Args))
+			for i, arg := range c.Args {
+				as, _ := arg.(*statement)
+				parts[i] = renderExpr(as)
+			}
+			buf.WriteString("case " + strings.Join(parts, ", ") + ":\n")
+		}
+		renderBlock(buf, c.Block)
+	}
+	buf.WriteString("}\n")
+}
+
+// renderTypeSwitch renders a "type-switch" node. Unlike a value switch, its
+// tag is asserted with ".(type)", and - when Name is set - it's bound in the
+// switch header rather than redeclared per case: each case's block still
+// carries its own narrowed variable-declaration of that name (so a backend
+// that doesn't special-case the header can use it directly), but re-emitting
+// that declaration here too would shadow the header's binding and, in a case
+// that never reads it, trip "declared and not used" in a way real Go exempts
+// only for the implicit per-case type-switch binding.
+func renderTypeSwitch(buf *bytes.Buffer, s *statement) {
+	buf.WriteString("switch ")
+	if s.Init != nil {
+		buf.WriteString(renderSimpleText(s.Init) + "; ")
+	}
+	if s.Name != "" {
+		buf.WriteString(s.Name + " := ")
+	}
+	buf.WriteString(renderExpr(s.Cond) + ".(type) {\n")
+	for _, c := range s.Cases {
+		if len(c.Args) == 0 {
+			buf.WriteString("default:\n")
+		} else {
+			parts := make([]string, len(c.Args))
+			for i, arg := range c.Args {
+				as, _ := arg.(*statement)
+				parts[i] = renderExpr(as)
+			}
+			buf.WriteString("case " + strings.Join(parts, ", ") + ":\n")
+		}
+		renderBlock(buf, skipBoundVarDecl(c.Block, s.Name))
+	}
+	buf.WriteString("}\n")
+}
+
+// skipBoundVarDecl drops a type-switch case block's leading
+// variable-declaration of name, if present, so renderTypeSwitch doesn't
+// redeclare what it already bound in the switch header.
+func skipBoundVarDecl(b *block, name string) *block {
+	if name == "" || b == nil || len(b.Stmts) == 0 {
+		return b
+	}
+	vd, ok := b.Stmts[0].(*statement)
+	if !ok || vd.Type != "variable-declaration" || vd.Name != name {
+		return b
+	}
+	return &block{ID: b.ID, Stmts: b.Stmts[1:]}
+}
+
+// compoundOps reports whether a node type is one assignType gives a compound
+// assignment ("+" for "+=", "-" for "-=", ...); renderSimpleText appends the
+// "=" back when rendering it.
+var compoundOps = map[string]bool{
+	"+": true, "-": true, "*": true, "/": true, "%": true,
+	"&": true, "|": true, "^": true, "<<": true, ">>": true, "&^": true,
+}
+
+// renderSimpleText renders a variable-declaration, assignment, ++/-- or bare
+// expression statement without a trailing newline, so it can be reused both
+// as a full statement and inline in a for-loop's init/post clause.
+func renderSimpleText(s *statement) string {
+	if s == nil {
+		return ""
+	}
+	switch s.Type {
+	case "variable-declaration":
+		return renderVarDecl(s)
+	case "assignment":
+		return fmt.Sprintf("%s = %s", s.Name, renderExpr(s.Init))
+	case "++", "--":
+		return fmt.Sprintf("%s%s", renderExpr(s.Left), s.Type)
+	default:
+		if compoundOps[s.Type] {
+			return fmt.Sprintf("%s %s= %s", s.Name, s.Type, renderExpr(s.Init))
+		}
+		return renderExpr(s)
+	}
+}
+
+// hasRealInit reports whether init is an actual initializer rather than the
+// empty placeholder statement (Type set, everything else zero) the DeclStmt
+// case in Visit synthesises for "var x T" with no value and no known zero
+// value for T.
+func hasRealInit(init *statement) bool {
+	if init == nil {
+		return false
+	}
+	return init.Value != "" || init.Left != nil || init.Right != nil ||
+		init.Expr != nil || init.Init != nil || init.Args != nil || init.Block != nil
+}
+
+// renderVarDecl picks between "name := init" and "var name type = init"
+// (or "var name type" with no initializer): the short form is used when the
+// initializer's own type already matches the declared type, since nothing
+// is lost by letting Go re-infer it.
+func renderVarDecl(s *statement) string {
+	init := hasRealInit(s.Init)
+	if init && s.DataType != nil && s.Init.Type == s.DataType.Name {
+		return fmt.Sprintf("%s := %s", s.Name, renderExpr(s.Init))
+	}
+	t := typeString(s.DataType)
+	if init {
+		return fmt.Sprintf("var %s %s = %s", s.Name, t, renderExpr(s.Init))
+	}
+	return fmt.Sprintf("var %s %s", s.Name, t)
+}
+
+// callNameInverse reverses funcNames, the table Visit uses to normalise
+// builtin-ish calls (fmt.Println, println) to a single canonical name.
+var callNameInverse = map[string]string{
+	"print": "println",
+}
+
+func renderCall(s *statement) string {
+	name := s.Name
+	if orig, ok := callNameInverse[name]; ok {
+		name = orig
+	}
+	args := make([]string, len(s.Args))
+	for i, arg := range s.Args {
+		as, _ := arg.(*statement)
+		args[i] = renderExpr(as)
+	}
+	return fmt.Sprintf("%s(%s)", name, strings.Join(args, ", "))
+}
+
+// renderExpr renders an expression node. Binary and unary operands are
+// always parenthesised: Visit treats *ast.ParenExpr as transparent (it just
+// descends into the wrapped expression without emitting a node of its own),
+// so the extra parens are free to add here and guarantee the rendered
+// precedence matches the tree's shape without needing to reason about
+// operator precedence ourselves.
+func renderExpr(s *statement) string {
+	if s == nil {
+		return ""
+	}
+	switch s.Type {
+	case "identifier":
+		return s.Value
+	case "string":
+		return strconv.Quote(s.Value)
+	case "char":
+		if rs := []rune(s.Value); len(rs) == 1 {
+			return strconv.QuoteRune(rs[0])
+		}
+		return strconv.Quote(s.Value)
+	case "binary":
+		return fmt.Sprintf("(%s %s %s)", renderExpr(s.Left), s.Value, renderExpr(s.Right))
+	case "unary":
+		return fmt.Sprintf("(%s%s)", backend.UnaryOp(s.Value, "!"), renderExpr(s.Expr))
+	case "index":
+		return fmt.Sprintf("%s[%s]", renderExpr(s.Left), renderExpr(s.Right))
+	case "selector":
+		return fmt.Sprintf("%s.%s", renderExpr(s.Left), s.Name)
+	case "type":
+		return s.Value
+	case "type-assert":
+		return fmt.Sprintf("%s.(%s)", renderExpr(s.Left), s.Value)
+	case "error":
+		return fmt.Sprintf("nil /* %s */", s.Value)
+	case "function-call":
+		return renderCall(s)
+	default:
+		// A literal whose Type is a resolved Go type name (go/types reports
+		// an untyped rune constant's default type as e.g. "int32", same as
+		// any other integer), rather than one of the cases above. Value is
+		// the literal's raw unquoted text, so a rune literal's Value is the
+		// decoded rune itself, not digits: quote it back if it doesn't
+		// already read as a number or a bool.
+		if backend.IsNumericLit(s.Value) || s.Value == "true" || s.Value == "false" {
+			return s.Value
+		}
+		if rs := []rune(s.Value); len(rs) == 1 {
+			return strconv.QuoteRune(rs[0])
+		}
+		return strconv.Quote(s.Value)
+	}
+}
+
+// typeString renders a dataType as a Go type. "void" (used for function
+// return types) renders as nothing; an unresolved/empty name falls back to
+// interface{} so the output always stays syntactically valid.
+func typeString(dt *dataType) string {
+	if dt == nil {
+		return ""
+	}
+	switch dt.Name {
+	case "void":
+		return ""
+	case "":
+		return "interface{}"
+	case "double":
+		return "float64"
+	case "char":
+		return "rune"
+	case "slice", "array":
+		return "[]" + typeString(dt.SubType)
+	case "map":
+		return "map[interface{}]" + typeString(dt.SubType)
+	case "pointer":
+		return "*" + typeString(dt.SubType)
+	case "channel":
+		return "chan " + typeString(dt.SubType)
+	default:
+		// Render always writes package main, so a go/types-qualified
+		// "main.T" has to be spelled T within it.
+		return strings.TrimPrefix(dt.Name, "main.")
+	}
+}
+
+// UnmarshalJSON reconstructs Stmts into their concrete type (*statement,
+// *structDecl or *errorNode) based on each element's "type" field: stmt is
+// just interface{}, so encoding/json has no way to pick one on its own.
+func (b *block) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		ID    int               `json:"id"`
+		Stmts []json.RawMessage `json:"statements"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	b.ID = raw.ID
+	b.Stmts = make([]stmt, 0, len(raw.Stmts))
+	for _, rm := range raw.Stmts {
+		s, err := unmarshalStmt(rm)
+		if err != nil {
+			return err
+		}
+		b.Stmts = append(b.Stmts, s)
+	}
+	return nil
+}
+
+// UnmarshalJSON mirrors block's: Args is a []stmt, so its elements need the
+// same type-field sniffing before the rest of statement can be decoded
+// normally via the alias trick (avoids recursing back into this method).
+func (s *statement) UnmarshalJSON(data []byte) error {
+	type alias statement
+	var raw struct {
+		alias
+		Args []json.RawMessage `json:"arguments,omitempty"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*s = statement(raw.alias)
+	for _, rm := range raw.Args {
+		a, err := unmarshalStmt(rm)
+		if err != nil {
+			return err
+		}
+		s.Args = append(s.Args, a)
+	}
+	return nil
+}
+
+func unmarshalStmt(data []byte) (stmt, error) {
+	var head struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(data, &head); err != nil {
+		return nil, err
+	}
+	switch head.Type {
+	case "error":
+		var e errorNode
+		if err := json.Unmarshal(data, &e); err != nil {
+			return nil, err
+		}
+		return &e, nil
+	case "struct-declaration":
+		var d structDecl
+		if err := json.Unmarshal(data, &d); err != nil {
+			return nil, err
+		}
+		return &d, nil
+	default:
+		var s statement
+		if err := json.Unmarshal(data, &s); err != nil {
+			return nil, err
+		}
+		return &s, nil
+	}
+}
@@ -0,0 +1,146 @@
+package superast
+
+import (
+	"bytes"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"regexp"
+	"testing"
+)
+
+var lineKeyRe = regexp.MustCompile(`"line":[0-9]+,?`)
+
+// stripLines drops the "line" key from a marshaled AST so two trees built
+// from differently-formatted source can be compared on shape alone.
+func stripLines(t *testing.T, a *AST) string {
+	return string(lineKeyRe.ReplaceAll(toJSON(t, a), nil))
+}
+
+// TestRenderRoundTrip parses src, builds its super-AST, renders that back to
+// Go via Render, then re-parses and re-visits the result: the two ASTs
+// should match once line numbers (which shift once the source is
+// regenerated) are ignored.
+func TestRenderRoundTrip(t *testing.T) {
+	const src = `package main
+
+func main() {
+	x := 1
+	y := 2
+	if x > y {
+		x = x - 1
+	} else {
+		x = 0
+	}
+	for i := 0; i < 3; i++ {
+		x = x + i
+	}
+	switch x {
+	case 0:
+		println("zero")
+	default:
+		println(x)
+	}
+}
+`
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "roundtrip.go", src, 0)
+	if err != nil {
+		t.Fatalf("Failed parsing source: %s", err)
+	}
+	want := NewAST(fset, f)
+	ast.Walk(want, f)
+
+	var buf bytes.Buffer
+	if err := Render(&buf, want.RootBlock); err != nil {
+		t.Fatalf("Render failed: %s", err)
+	}
+
+	fset2 := token.NewFileSet()
+	f2, err := parser.ParseFile(fset2, "roundtrip.go", buf.Bytes(), 0)
+	if err != nil {
+		t.Fatalf("Rendered source failed to parse: %s\n%s", err, buf.String())
+	}
+	got := NewAST(fset2, f2)
+	ast.Walk(got, f2)
+
+	if w, g := stripLines(t, want), stripLines(t, got); w != g {
+		t.Errorf("Round-tripped AST differs:\nwant %s\ngot  %s", w, g)
+	}
+}
+
+// TestRenderTypeSwitchRoundTrip checks that a type switch round-trips to
+// real, type-checkable Go: rendering it the same way as a value switch
+// (case int: ...) used to produce "int (type) is not an expression" and a
+// missing type assertion.
+func TestRenderTypeSwitchRoundTrip(t *testing.T) {
+	const src = `package main
+
+func main() {
+	var x interface{} = 1
+	switch v := x.(type) {
+	case int:
+		println(v)
+	case string:
+		println(v)
+	default:
+		println(0)
+	}
+}
+`
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "typeswitch.go", src, 0)
+	if err != nil {
+		t.Fatalf("Failed parsing source: %s", err)
+	}
+	a := NewAST(fset, f)
+	ast.Walk(a, f)
+
+	var buf bytes.Buffer
+	if err := Render(&buf, a.RootBlock); err != nil {
+		t.Fatalf("Render failed: %s", err)
+	}
+
+	fset2 := token.NewFileSet()
+	f2, err := parser.ParseFile(fset2, "typeswitch.go", buf.Bytes(), 0)
+	if err != nil {
+		t.Fatalf("Rendered source failed to parse: %s\n%s", err, buf.String())
+	}
+	info := &types.Info{Types: make(map[ast.Expr]types.TypeAndValue)}
+	conf := types.Config{Importer: importer.Default()}
+	if _, err := conf.Check("main", fset2, []*ast.File{f2}, info); err != nil {
+		t.Errorf("Rendered source failed to type-check: %s\n%s", err, buf.String())
+	}
+}
+
+// TestRenderErrorNode checks that an errorNode, which stands in for a
+// construct Render has no source to reconstruct, comes out as a comment
+// rather than breaking the rest of the output.
+func TestRenderErrorNode(t *testing.T) {
+	const src = `package main
+
+import "os"
+
+func main() {
+	defer os.Exit(0)
+	println(1)
+}
+`
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "broken.go", src, 0)
+	if err != nil {
+		t.Fatalf("Failed parsing source: %s", err)
+	}
+	a := NewAST(fset, f)
+	ast.Walk(a, f)
+
+	var buf bytes.Buffer
+	if err := Render(&buf, a.RootBlock); err != nil {
+		t.Fatalf("Render failed: %s", err)
+	}
+	if _, err := parser.ParseFile(token.NewFileSet(), "broken.go", buf.Bytes(), 0); err != nil {
+		t.Errorf("Rendered source failed to parse: %s\n%s", err, buf.String())
+	}
+}
@@ -1,8 +1,11 @@
 package superast
 
 import (
+	"fmt"
 	"go/ast"
+	"go/importer"
 	"go/token"
+	"go/types"
 	"log"
 	"strconv"
 	"strings"
@@ -21,6 +24,10 @@ type block struct {
 type dataType struct {
 	ID   int    `json:"id"`
 	Name string `json:"name"`
+	// SubType holds the element type of a composite type: the element type
+	// of a slice/array/channel, the value type of a map, or the pointee of
+	// a pointer. Left nil for basic and named types.
+	SubType *dataType `json:"data-type,omitempty"`
 }
 
 type varDecl struct {
@@ -45,13 +52,28 @@ type statement struct {
 	Left     *statement `json:"left,omitempty"`
 	Right    *statement `json:"right,omitempty"`
 	Block    *block     `json:"block,omitempty"`
+	// Cond/Then/Else are used by "conditional" nodes (name "if"), Post by
+	// "for" nodes, Expr by "return" nodes, and Cases by "switch" nodes: each
+	// entry is itself a statement of type "case" whose Args hold the case
+	// expressions (empty for "default") and whose Block holds its body.
+	Cond  *statement   `json:"condition,omitempty"`
+	Then  *block       `json:"then,omitempty"`
+	Else  *block       `json:"else,omitempty"`
+	Post  *statement   `json:"post,omitempty"`
+	Expr  *statement   `json:"expression,omitempty"`
+	Cases []*statement `json:"cases,omitempty"`
 }
 
-type identifier struct {
-	ID       int    `json:"id"`
-	Line     int    `json:"line"`
-	Type     string `json:"type"`
-	Value    string `json:"value"`
+// errorNode stands in for a construct the visitor could not translate. It's
+// emitted inline, in place of the node that triggered it, so the rest of the
+// tree around it still comes out; it's also collected in AST.Errors so
+// callers can tell, after a full walk, whether the output is complete.
+type errorNode struct {
+	ID    int    `json:"id"`
+	Line  int    `json:"line"`
+	Type  string `json:"type"`
+	Value string `json:"value"`
+	Desc  string `json:"description"`
 }
 
 type structDecl struct {
@@ -68,9 +90,49 @@ type AST struct {
 	nodeStack  []ast.Node
 	stmtsStack []*[]stmt
 	fset       *token.FileSet
+	// info is the package's go/types checking result, used by exprType and
+	// resolveType to resolve an identifier's, selector's or expression's
+	// real type. go/types already does its own lexical scoping for any
+	// identifier it can see, so there's no separate scope table kept
+	// alongside it here - nil only when NewAST was called without any files
+	// to check.
+	info *types.Info
+	// Strict makes the visitor abort via log.Fatalf on the first construct
+	// it can't translate, matching its old all-or-nothing behavior. Left
+	// false (the default), it instead synthesises an errorNode in place of
+	// the offending construct and keeps walking.
+	Strict bool
+	// Mode controls how much of a *ast.FuncDecl gets translated: the zero
+	// value, ParseFull, walks everything; ParseHeader keeps the signature
+	// but leaves the body unwalked; ParseExported walks like ParseFull but
+	// skips function declarations whose name isn't exported. Set by a
+	// Translator before the walk, or
+	// left at ParseFull for a bare NewAST caller.
+	Mode ParseMode
+	// Errors accumulates every errorNode emitted during the walk, in the
+	// order encountered.
+	Errors []errorNode
+	// loopDepth counts how many enclosing for/range loops are currently
+	// being walked, so a break or continue outside of any loop can be
+	// reported instead of emitted.
+	loopDepth int
+	// switchDepth counts how many enclosing switch statements are
+	// currently being walked. A break is also valid directly inside a
+	// switch case - where it renders as the keyword ending that case,
+	// same as a real switch/case/break target - even with no loop around
+	// it; continue is unaffected, since it always targets an enclosing
+	// loop rather than the switch.
+	switchDepth int
 }
 
-func NewAST(fset *token.FileSet) *AST {
+// NewAST prepares a visitor for fset. If one or more parsed files are given,
+// they are type-checked as a single package first and the resulting
+// *types.Info is kept alongside the FileSet, letting Visit resolve the
+// canonical type of identifiers, literals and calls instead of guessing from
+// syntax alone. Type-check errors are logged and otherwise ignored: the
+// visitor falls back to its string-based heuristics for anything it could
+// not resolve.
+func NewAST(fset *token.FileSet, files ...*ast.File) *AST {
 	a := &AST{
 		curID: 1,
 		fset:  fset,
@@ -80,9 +142,32 @@ func NewAST(fset *token.FileSet) *AST {
 		},
 	}
 	a.pushStmts(&a.RootBlock.Stmts)
+	if len(files) > 0 {
+		a.info = checkTypes(fset, files)
+	}
 	return a
 }
 
+// checkTypes type-checks files as a single package and returns the resulting
+// type information. It never returns nil: when type-checking reports errors,
+// the returned info holds whatever go/types managed to resolve anyway.
+func checkTypes(fset *token.FileSet, files []*ast.File) *types.Info {
+	info := &types.Info{
+		Types:     make(map[ast.Expr]types.TypeAndValue),
+		Defs:      make(map[*ast.Ident]types.Object),
+		Uses:      make(map[*ast.Ident]types.Object),
+		Implicits: make(map[ast.Node]types.Object),
+	}
+	conf := types.Config{
+		Importer: importer.Default(),
+		Error:    func(err error) { log.Printf("type-check: %v", err) },
+	}
+	if _, err := conf.Check(files[0].Name.Name, fset, files, info); err != nil {
+		log.Printf("type-check failed: %v", err)
+	}
+	return info
+}
+
 func (a *AST) newID() int {
 	i := a.curID
 	a.curID++
@@ -126,6 +211,35 @@ func (a *AST) popStmts() {
 	a.stmtsStack = a.stmtsStack[:len(a.stmtsStack)-1]
 }
 
+// addError records that node could not be translated. In Strict mode it
+// aborts immediately via log.Fatalf, matching the visitor's old behavior;
+// otherwise it synthesises an errorNode in place of node, appends it to the
+// current statement list so it shows up inline in the JSON tree, and lets
+// the walk continue.
+func (a *AST) addError(node ast.Node, value, desc string) {
+	if a.Strict {
+		log.Fatalf("%s: %s", desc, value)
+	}
+	e := errorNode{
+		ID:    a.newID(),
+		Line:  a.fset.Position(node.Pos()).Line,
+		Type:  "error",
+		Value: value,
+		Desc:  desc,
+	}
+	a.Errors = append(a.Errors, e)
+	a.addStmt(&e)
+}
+
+// Err reports whether any construct failed to translate during the walk. It
+// returns nil when AST.Errors is empty.
+func (a *AST) Err() error {
+	if len(a.Errors) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%d error(s) while translating", len(a.Errors))
+}
+
 func strUnquote(s string) string {
 	u, err := strconv.Unquote(s)
 	if err != nil {
@@ -156,27 +270,33 @@ var funcNames = map[string]string{
 }
 
 type field struct {
-	varName, typeName string
+	varName  string
+	dataType *dataType
 }
 
-func flattenFieldList(fieldList *ast.FieldList) []field {
+// flattenFieldList resolves a field list (struct fields, function parameters
+// or results) into one field per name, recursing through slices, arrays,
+// maps, pointers and channels via dataType.SubType the same way a variable
+// declaration's type does. A field with no go/types info (NewAST was called
+// without files) falls back to the bare syntax name, same as exprToString.
+func (a *AST) flattenFieldList(fieldList *ast.FieldList) []field {
 	if fieldList == nil {
 		return nil
 	}
 	var fields []field
 	for _, f := range fieldList.List {
-		t := exprToString(f.Type)
+		resolve := func() *dataType {
+			if dt := a.exprType(f.Type); dt != nil {
+				return dt
+			}
+			return &dataType{ID: a.newID(), Name: exprToString(f.Type)}
+		}
 		if len(f.Names) == 0 {
-			fields = append(fields, field{
-				varName:  "",
-				typeName: t,
-			})
+			fields = append(fields, field{dataType: resolve()})
+			continue
 		}
 		for _, n := range f.Names {
-			fields = append(fields, field{
-				varName:  n.Name,
-				typeName: t,
-			})
+			fields = append(fields, field{varName: n.Name, dataType: resolve()})
 		}
 	}
 	return fields
@@ -196,15 +316,237 @@ var zeroValues = map[string]string{
 	"string": `""`,
 }
 
+// resolveType turns a go/types.Type into a dataType, recursing through
+// slices, arrays, maps, pointers and channels via SubType so e.g. []int
+// comes out as {name:"slice", data-type:{name:"int"}}. Named types and
+// function signatures are reported by their types.Type.String() form;
+// nil is returned for nil.
+func (a *AST) resolveType(t types.Type) *dataType {
+	if t == nil {
+		return nil
+	}
+	switch u := t.(type) {
+	case *types.Slice:
+		return &dataType{ID: a.newID(), Name: "slice", SubType: a.resolveType(u.Elem())}
+	case *types.Array:
+		return &dataType{ID: a.newID(), Name: "array", SubType: a.resolveType(u.Elem())}
+	case *types.Map:
+		return &dataType{ID: a.newID(), Name: "map", SubType: a.resolveType(u.Elem())}
+	case *types.Pointer:
+		return &dataType{ID: a.newID(), Name: "pointer", SubType: a.resolveType(u.Elem())}
+	case *types.Chan:
+		return &dataType{ID: a.newID(), Name: "channel", SubType: a.resolveType(u.Elem())}
+	case *types.Basic:
+		return &dataType{ID: a.newID(), Name: basicTypeName(u)}
+	default:
+		return &dataType{ID: a.newID(), Name: t.String()}
+	}
+}
+
+// basicTypeName maps a go/types.Basic to this package's IR vocabulary
+// ("int", "double", "char", "string", "bool"), so backends only ever see
+// the names they already know how to render instead of every spelling
+// go/types itself uses (float64, rune, byte, uint32, ...). Untyped constant
+// kinds ("untyped int", "untyped float", ...) and basic types outside that
+// vocabulary (complex64, ...) are passed through under their go/types name.
+func basicTypeName(b *types.Basic) string {
+	name := b.Name()
+	if strings.HasPrefix(name, "untyped ") {
+		return name
+	}
+	switch name {
+	case "bool", "string":
+		return name
+	case "float32", "float64":
+		return "double"
+	case "rune", "byte":
+		return "char"
+	default:
+		if strings.Contains(name, "int") {
+			return "int"
+		}
+		return name
+	}
+}
+
+// rangeElemKind reports the resolved go/types category of x - "slice",
+// "array", "map", "channel", "string", "other" for any other resolved type,
+// or "" when nothing was resolved -
+// without allocating the dataType IDs a full exprType call would. It backs
+// RangeStmt's check for whether x can be lowered to an index-based for
+// loop, which only wants to know x's kind and would otherwise waste IDs on
+// a dataType tree it's about to throw away.
+func (a *AST) rangeElemKind(x ast.Expr) string {
+	if a.info == nil || x == nil {
+		return ""
+	}
+	tv, ok := a.info.Types[x]
+	if !ok || tv.Type == nil {
+		return ""
+	}
+	switch u := tv.Type.Underlying().(type) {
+	case *types.Slice:
+		return "slice"
+	case *types.Array:
+		return "array"
+	case *types.Map:
+		return "map"
+	case *types.Chan:
+		return "channel"
+	case *types.Basic:
+		if u.Info()&types.IsString != 0 {
+			return "string"
+		}
+	}
+	return "other"
+}
+
+// exprType resolves the canonical type name of an expression via go/types,
+// returning nil when no type info was collected (NewAST was called without
+// files) or the expression has no resolved type.
+func (a *AST) exprType(x ast.Expr) *dataType {
+	if a.info == nil || x == nil {
+		return nil
+	}
+	if tv, ok := a.info.Types[x]; ok && tv.Type != nil {
+		return a.resolveType(tv.Type)
+	}
+	if id, ok := x.(*ast.Ident); ok {
+		if obj := a.info.Uses[id]; obj != nil {
+			return a.resolveType(obj.Type())
+		}
+		if obj := a.info.Defs[id]; obj != nil {
+			return a.resolveType(obj.Type())
+		}
+	}
+	return nil
+}
+
+// callSignature returns the resolved *types.Func behind a call's callee
+// expression, or nil if it can't be resolved (no type info, builtin, or a
+// value of function type rather than a named function).
+func (a *AST) callSignature(fun ast.Expr) *types.Func {
+	if a.info == nil {
+		return nil
+	}
+	var id *ast.Ident
+	switch f := fun.(type) {
+	case *ast.Ident:
+		id = f
+	case *ast.SelectorExpr:
+		id = f.Sel
+	default:
+		return nil
+	}
+	fn, _ := a.info.Uses[id].(*types.Func)
+	return fn
+}
+
+// visitExpr walks a single expression through the normal Visit dispatch (so
+// it picks up BinaryExpr/UnaryExpr/IndexExpr/CallExpr/Ident/BasicLit handling
+// below) and returns the lone statement it produced. It backs anywhere an
+// expression is needed outside the regular block statement list: conditions,
+// loop init/post clauses, switch tags/case values and return values.
+func (a *AST) visitExpr(x ast.Expr) *statement {
+	if x == nil {
+		return nil
+	}
+	var collected []stmt
+	a.pushStmts(&collected)
+	ast.Walk(a, x)
+	a.popStmts()
+	if len(collected) == 0 {
+		return nil
+	}
+	switch s := collected[0].(type) {
+	case *statement:
+		return s
+	case *errorNode:
+		// addError already recorded this in a.Errors; re-wrap it as a
+		// statement (Init and friends are typed *statement, not the stmt
+		// interface an errorNode satisfies) instead of dropping it here,
+		// so a construct that fails in expression position - e.g. a
+		// composite literal used as a variable's initializer - still
+		// shows up inline in the JSON tree instead of silently looking
+		// like a missing initializer.
+		return &statement{ID: s.ID, Line: s.Line, Type: "error", Value: s.Desc + ": " + s.Value}
+	default:
+		return nil
+	}
+}
+
+// inferType makes a best-effort guess at the data type of an already-built
+// expression node. It's the fallback varType uses when no go/types info was
+// collected for the enclosing file.
+func inferType(v *statement) string {
+	if v == nil {
+		return ""
+	}
+	switch v.Type {
+	case "int", "double", "char", "string":
+		return v.Type
+	}
+	return ""
+}
+
+// varType resolves the type of a newly declared variable. It prefers the
+// go/types-resolved type of the declaring identifier, falling back to
+// inferType's syntax-only guess at the initializer when no type info was
+// collected.
+func (a *AST) varType(lhs ast.Expr, value *statement) *dataType {
+	if id, ok := lhs.(*ast.Ident); ok {
+		if dt := a.exprType(id); dt != nil {
+			return dt
+		}
+	}
+	return &dataType{ID: a.newID(), Name: inferType(value)}
+}
+
+// buildSimpleStmt converts the init/post clause of a for-loop (an assignment
+// or increment, generally) into a standalone statement node.
+func (a *AST) buildSimpleStmt(s ast.Stmt) *statement {
+	switch x := s.(type) {
+	case *ast.AssignStmt:
+		if len(x.Lhs) != 1 || len(x.Rhs) != 1 {
+			return nil
+		}
+		name := exprToString(x.Lhs[0])
+		value := a.visitExpr(x.Rhs[0])
+		if x.Tok == token.DEFINE {
+			return &statement{
+				ID:       a.newID(),
+				Type:     "variable-declaration",
+				Name:     name,
+				DataType: a.varType(x.Lhs[0], value),
+				Init:     value,
+			}
+		}
+		return &statement{ID: a.newID(), Type: assignType(x.Tok), Name: name, Init: value}
+	case *ast.IncDecStmt:
+		t := "++"
+		if x.Tok == token.DEC {
+			t = "--"
+		}
+		return &statement{ID: a.newID(), Type: t, Left: a.visitExpr(x.X)}
+	case *ast.ExprStmt:
+		return a.visitExpr(x.X)
+	default:
+		return nil
+	}
+}
+
+// assignType maps a non-define assignment token ('=', '+=', ...) to the node
+// type used for updating an already-declared variable, so that e.g. "x += 1"
+// is distinguishable from a fresh "x := 1" declaration.
+func assignType(tok token.Token) string {
+	if tok == token.ASSIGN {
+		return "assignment"
+	}
+	return strings.TrimSuffix(tok.String(), "=")
+}
+
 func (a *AST) Visit(node ast.Node) ast.Visitor {
-	parentNode := a.curNode()
 	if node == nil {
-		switch parentNode.(type) {
-		case *ast.CallExpr:
-			a.popStmts()
-		case *ast.FuncDecl:
-			a.popStmts()
-		}
 		a.popNode()
 		return nil
 	}
@@ -214,19 +556,31 @@ func (a *AST) Visit(node ast.Node) ast.Visitor {
 	case *ast.File:
 		pname := x.Name.Name
 		if pname != "main" {
-			log.Fatalf(`Package name is not "main": "%s"`, pname)
+			a.addError(x, pname, `package name is not "main"`)
 		}
 		imports := x.Imports
 		for _, imp := range imports {
 			path := strUnquote(imp.Path.Value)
 			if _, e := allowedImports[path]; !e {
-				log.Fatalf(`Import path not allowed: "%s"`, path)
+				a.addError(imp, path, "import not allowed")
 			}
 		}
+		for _, decl := range x.Decls {
+			ast.Walk(a, decl)
+		}
+		return nil
 	case *ast.TypeSpec:
 		n := ""
 		if x.Name != nil {
 			n = exprToString(x.Name)
+			// Prefer go/types' package-qualified name (e.g. "main.Point")
+			// over the bare declared name, so a variable of this type -
+			// whose own dataType is always resolved through exprType - is
+			// rendered with a type name that actually matches this
+			// declaration's.
+			if dt := a.exprType(x.Name); dt != nil {
+				n = dt.Name
+			}
 		}
 		switch t := x.Type.(type) {
 		case *ast.StructType:
@@ -236,40 +590,86 @@ func (a *AST) Visit(node ast.Node) ast.Visitor {
 				Type: "struct-declaration",
 				Name: n,
 			}
-			for _, f := range flattenFieldList(t.Fields) {
+			for _, f := range a.flattenFieldList(t.Fields) {
 				attr := varDecl{
-					ID:   a.newID(),
-					Name: f.varName,
-					DataType: &dataType{
-						ID:   a.newID(),
-						Name: f.typeName,
-					},
+					ID:       a.newID(),
+					Name:     f.varName,
+					DataType: f.dataType,
 				}
 				decl.Attrs = append(decl.Attrs, attr)
 			}
 			a.addStmt(decl)
 		}
+		return nil
 	case *ast.Ident:
-		switch parentNode.(type) {
-		case *ast.CallExpr:
-		default:
-			return nil
+		a.addStmt(&statement{
+			ID:       a.newID(),
+			Line:     pos.Line,
+			Type:     "identifier",
+			Value:    x.Name,
+			DataType: a.exprType(x),
+		})
+		return nil
+	case *ast.BasicLit:
+		t, _ := basicLitName[x.Kind]
+		if dt := a.exprType(x); dt != nil {
+			t = dt.Name
 		}
-		id := &identifier{
+		a.addStmt(&statement{ID: a.newID(), Line: pos.Line, Type: t, Value: strUnquote(x.Value)})
+		return nil
+	case *ast.BinaryExpr:
+		a.addStmt(&statement{
 			ID:    a.newID(),
 			Line:  pos.Line,
-			Type:  "identifier",
-			Value: x.Name,
+			Type:  "binary",
+			Value: x.Op.String(),
+			Left:  a.visitExpr(x.X),
+			Right: a.visitExpr(x.Y),
+		})
+		return nil
+	case *ast.UnaryExpr:
+		t := x.Op.String()
+		switch x.Op {
+		case token.ADD:
+			t = "pos"
+		case token.SUB:
+			t = "neg"
+		case token.NOT:
+			t = "not"
+		case token.AND:
+			t = "addr"
 		}
-		a.addStmt(id)
-	case *ast.BasicLit:
-		lit := &statement{
+		a.addStmt(&statement{ID: a.newID(), Line: pos.Line, Type: "unary", Value: t, Expr: a.visitExpr(x.X)})
+		return nil
+	case *ast.SelectorExpr:
+		// p.X where p is a struct value: Left holds p, Name the field, and
+		// DataType its go/types-resolved field type (exprType resolves the
+		// whole SelectorExpr, not just x.Sel, since go/types only records a
+		// TypeAndValue against the full selector expression). A qualified
+		// identifier like fmt.Println never reaches here: CallExpr resolves
+		// its callee name via exprToString before walking arguments, so this
+		// only fires for a selector used as a value.
+		a.addStmt(&statement{
+			ID:       a.newID(),
+			Line:     pos.Line,
+			Type:     "selector",
+			Name:     x.Sel.Name,
+			Left:     a.visitExpr(x.X),
+			DataType: a.exprType(x),
+		})
+		return nil
+	case *ast.IndexExpr:
+		a.addStmt(&statement{
 			ID:    a.newID(),
 			Line:  pos.Line,
-			Type:  "string",
-			Value: strUnquote(x.Value),
-		}
-		a.addStmt(lit)
+			Type:  "index",
+			Left:  a.visitExpr(x.X),
+			Right: a.visitExpr(x.Index),
+		})
+		return nil
+	case *ast.ParenExpr:
+		// Transparent: the tree shape already encodes precedence, so just
+		// descend into the wrapped expression without adding a node of our own.
 	case *ast.CallExpr:
 		name := exprToString(x.Fun)
 		if newname, e := funcNames[name]; e {
@@ -281,10 +681,26 @@ func (a *AST) Visit(node ast.Node) ast.Visitor {
 			Type: "function-call",
 			Name: name,
 		}
+		if fn := a.callSignature(x.Fun); fn != nil {
+			if sig, ok := fn.Type().(*types.Signature); ok {
+				switch sig.Results().Len() {
+				case 0:
+					call.RetType = &dataType{ID: a.newID(), Name: "void"}
+				case 1:
+					call.RetType = a.resolveType(sig.Results().At(0).Type())
+				}
+			}
+		}
+		for _, arg := range x.Args {
+			call.Args = append(call.Args, a.visitExpr(arg))
+		}
 		a.addStmt(call)
-		a.pushStmts(&call.Args)
+		return nil
 	case *ast.FuncDecl:
 		name := x.Name.Name
+		if a.Mode == ParseExported && !ast.IsExported(name) {
+			return nil
+		}
 		fn := &statement{
 			ID:   a.newID(),
 			Line: pos.Line,
@@ -298,89 +714,363 @@ func (a *AST) Visit(node ast.Node) ast.Visitor {
 				Stmts: make([]stmt, 0),
 			},
 		}
-		for _, f := range flattenFieldList(x.Type.Params) {
+		for _, f := range a.flattenFieldList(x.Type.Params) {
 			param := varDecl{
-				ID:   a.newID(),
-				Name: f.varName,
-				DataType: &dataType{
-					ID:   a.newID(),
-					Name: f.typeName,
-				},
+				ID:       a.newID(),
+				Name:     f.varName,
+				DataType: f.dataType,
 			}
 			fn.Params = append(fn.Params, param)
 		}
-		results := flattenFieldList(x.Type.Results)
+		results := a.flattenFieldList(x.Type.Results)
 		switch len(results) {
 		case 0:
 			fn.RetType.Name = "void"
 		case 1:
-			fn.RetType.Name = results[0].typeName
+			fn.RetType.Name = results[0].dataType.Name
+			fn.RetType.SubType = results[0].dataType.SubType
+		default:
+			a.addError(x.Type.Results, fmt.Sprintf("%d", len(results)), "multi-value return type not supported")
 		}
 		a.addStmt(fn)
+		if a.Mode == ParseHeader {
+			return nil
+		}
 		a.pushStmts(&fn.Block.Stmts)
+		ast.Walk(a, x.Body)
+		a.popStmts()
+		return nil
 	case *ast.DeclStmt:
-		log.Printf("%#v", x.Decl)
-		gd, _ := x.Decl.(*ast.GenDecl)
+		gd, ok := x.Decl.(*ast.GenDecl)
+		if !ok {
+			a.addError(x, fmt.Sprintf("%T", x.Decl), "unsupported declaration")
+			return nil
+		}
 		for _, spec := range gd.Specs {
 			switch s := spec.(type) {
 			case *ast.ValueSpec:
-				t := exprToString(s.Type)
 				for i, id := range s.Names {
-					n := exprToString(id)
-					v, _ := zeroValues[t]
+					var value *statement
 					if s.Values != nil {
-						v = exprToString(s.Values[i])
+						value = a.visitExpr(s.Values[i])
+					}
+					dt := a.exprType(id)
+					if dt == nil {
+						// No go/types info for this identifier: fall back
+						// to the type written at the declaration, or (for
+						// "var x = someExpr", which has none) a guess at
+						// the initializer's own type.
+						t := exprToString(s.Type)
+						if t == "" {
+							t = inferType(value)
+						}
+						dt = &dataType{ID: a.newID(), Name: t}
+					}
+					init := value
+					if init == nil {
+						init = &statement{ID: a.newID(), Type: dt.Name, Value: zeroValues[dt.Name]}
 					}
 					decl := &statement{
-						ID:   a.newID(),
-						Line: pos.Line,
-						Type: "variable-declaration",
-						Name: n,
-						DataType: &dataType{
-							ID:   a.newID(),
-							Name: t,
-						},
-						Init: &statement{
-							ID:    a.newID(),
-							Type:  t,
-							Value: v,
-						},
+						ID:       a.newID(),
+						Line:     pos.Line,
+						Type:     "variable-declaration",
+						Name:     id.Name,
+						DataType: dt,
+						Init:     init,
 					}
 					a.addStmt(decl)
 				}
 			}
 		}
+		return nil
 	case *ast.AssignStmt:
-		for i, expr := range x.Lhs {
-			n := exprToString(expr)
-			l, _ := x.Rhs[i].(*ast.BasicLit)
-			value := strUnquote(l.Value)
-			typeName, _ := basicLitName[l.Kind]
-			asg := &statement{
+		if len(x.Rhs) != len(x.Lhs) {
+			a.addError(x, fmt.Sprintf("%d = %d", len(x.Lhs), len(x.Rhs)), "multi-value assignment not supported")
+			return nil
+		}
+		for i, lhs := range x.Lhs {
+			n := exprToString(lhs)
+			value := a.visitExpr(x.Rhs[i])
+			if x.Tok == token.DEFINE {
+				a.addStmt(&statement{
+					ID:       a.newID(),
+					Line:     pos.Line,
+					Type:     "variable-declaration",
+					Name:     n,
+					DataType: a.varType(lhs, value),
+					Init:     value,
+				})
+				continue
+			}
+			// '=' or a compound assignment ('+=', '-=', ...) to an
+			// already-declared variable; keep it distinct from a fresh
+			// ":=" declaration.
+			a.addStmt(&statement{
 				ID:   a.newID(),
 				Line: pos.Line,
-				Type: "variable-declaration",
+				Type: assignType(x.Tok),
 				Name: n,
+				Init: value,
+			})
+		}
+		return nil
+	case *ast.IfStmt:
+		cond := &statement{
+			ID:   a.newID(),
+			Line: pos.Line,
+			Type: "conditional",
+			Name: "if",
+			Cond: a.visitExpr(x.Cond),
+			Then: &block{ID: a.newID(), Stmts: make([]stmt, 0)},
+		}
+		a.addStmt(cond)
+		a.pushStmts(&cond.Then.Stmts)
+		ast.Walk(a, x.Body)
+		a.popStmts()
+		if x.Else != nil {
+			cond.Else = &block{ID: a.newID(), Stmts: make([]stmt, 0)}
+			a.pushStmts(&cond.Else.Stmts)
+			ast.Walk(a, x.Else)
+			a.popStmts()
+		}
+		return nil
+	case *ast.ForStmt:
+		f := &statement{
+			ID:    a.newID(),
+			Line:  pos.Line,
+			Type:  "for",
+			Block: &block{ID: a.newID(), Stmts: make([]stmt, 0)},
+		}
+		if x.Init != nil {
+			f.Init = a.buildSimpleStmt(x.Init)
+		}
+		if x.Cond != nil {
+			f.Cond = a.visitExpr(x.Cond)
+		}
+		if x.Post != nil {
+			f.Post = a.buildSimpleStmt(x.Post)
+		}
+		a.addStmt(f)
+		a.pushStmts(&f.Block.Stmts)
+		a.loopDepth++
+		ast.Walk(a, x.Body)
+		a.loopDepth--
+		a.popStmts()
+		return nil
+	case *ast.RangeStmt:
+		if k := a.rangeElemKind(x.X); k != "" && k != "slice" && k != "array" {
+			a.addError(x, exprToString(x.X), "range over "+k+" not supported (only slices and arrays are)")
+			return nil
+		}
+		xsName := exprToString(x.X)
+		indexName := "_i"
+		if id, ok := x.Key.(*ast.Ident); ok && id.Name != "_" {
+			indexName = id.Name
+		}
+		f := &statement{
+			ID:   a.newID(),
+			Line: pos.Line,
+			Type: "for",
+			Init: &statement{
+				ID:   a.newID(),
+				Type: "variable-declaration",
+				Name: indexName,
 				DataType: &dataType{
 					ID:   a.newID(),
-					Name: typeName,
+					Name: "int",
 				},
+				Init: &statement{ID: a.newID(), Type: "int", Value: "0"},
+			},
+			Cond: &statement{
+				ID:    a.newID(),
+				Type:  "binary",
+				Value: "<",
+				Left:  &statement{ID: a.newID(), Type: "identifier", Value: indexName},
+				Right: &statement{
+					ID:   a.newID(),
+					Type: "function-call",
+					Name: "len",
+					Args: []stmt{&statement{ID: a.newID(), Type: "identifier", Value: xsName}},
+				},
+			},
+			Post:  &statement{ID: a.newID(), Type: "++", Left: &statement{ID: a.newID(), Type: "identifier", Value: indexName}},
+			Block: &block{ID: a.newID(), Stmts: make([]stmt, 0)},
+		}
+		a.addStmt(f)
+		if id, ok := x.Value.(*ast.Ident); ok && id.Name != "_" {
+			f.Block.Stmts = append(f.Block.Stmts, &statement{
+				ID:   a.newID(),
+				Type: "variable-declaration",
+				Name: id.Name,
 				Init: &statement{
 					ID:    a.newID(),
-					Type:  typeName,
-					Value: value,
+					Type:  "index",
+					Left:  &statement{ID: a.newID(), Type: "identifier", Value: xsName},
+					Right: &statement{ID: a.newID(), Type: "identifier", Value: indexName},
 				},
+			})
+		}
+		a.pushStmts(&f.Block.Stmts)
+		a.loopDepth++
+		ast.Walk(a, x.Body)
+		a.loopDepth--
+		a.popStmts()
+		return nil
+	case *ast.SwitchStmt:
+		sw := &statement{ID: a.newID(), Line: pos.Line, Type: "switch"}
+		if x.Init != nil {
+			sw.Init = a.buildSimpleStmt(x.Init)
+		}
+		if x.Tag != nil {
+			sw.Cond = a.visitExpr(x.Tag)
+		}
+		for _, c := range x.Body.List {
+			cc, ok := c.(*ast.CaseClause)
+			if !ok {
+				continue
+			}
+			caseStmt := &statement{ID: a.newID(), Type: "case", Block: &block{ID: a.newID(), Stmts: make([]stmt, 0)}}
+			for _, v := range cc.List {
+				caseStmt.Args = append(caseStmt.Args, a.visitExpr(v))
+			}
+			sw.Cases = append(sw.Cases, caseStmt)
+			a.pushStmts(&caseStmt.Block.Stmts)
+			a.switchDepth++
+			for _, s := range cc.Body {
+				ast.Walk(a, s)
 			}
-			a.addStmt(asg)
+			a.switchDepth--
+			a.popStmts()
 		}
+		a.addStmt(sw)
+		return nil
+	case *ast.TypeSwitchStmt:
+		var tag ast.Expr
+		var bindName string
+		switch asn := x.Assign.(type) {
+		case *ast.AssignStmt:
+			if len(asn.Lhs) == 1 {
+				if id, ok := asn.Lhs[0].(*ast.Ident); ok {
+					bindName = id.Name
+				}
+			}
+			if len(asn.Rhs) == 1 {
+				if ta, ok := asn.Rhs[0].(*ast.TypeAssertExpr); ok {
+					tag = ta.X
+				}
+			}
+		case *ast.ExprStmt:
+			if ta, ok := asn.X.(*ast.TypeAssertExpr); ok {
+				tag = ta.X
+			}
+		}
+		// Type "type-switch" distinguishes this from a plain "switch": its
+		// Cases' Args are type names, not values, so a consumer can't
+		// evaluate them against Cond by equality the way it would a value
+		// switch. Name, when set, is the "v" in "switch v := x.(type)"; each
+		// case's block also starts with its own variable-declaration of v,
+		// narrowed to that case's type.
+		sw := &statement{ID: a.newID(), Line: pos.Line, Type: "type-switch"}
+		if bindName != "" && bindName != "_" {
+			sw.Name = bindName
+		}
+		if x.Init != nil {
+			sw.Init = a.buildSimpleStmt(x.Init)
+		}
+		if tag != nil {
+			sw.Cond = a.visitExpr(tag)
+		}
+		for _, c := range x.Body.List {
+			cc, ok := c.(*ast.CaseClause)
+			if !ok {
+				continue
+			}
+			caseStmt := &statement{ID: a.newID(), Type: "case", Block: &block{ID: a.newID(), Stmts: make([]stmt, 0)}}
+			for _, t := range cc.List {
+				// Type "type" marks this as a type name, not a value reference
+				// to an identifier of that name.
+				caseStmt.Args = append(caseStmt.Args, &statement{ID: a.newID(), Type: "type", Value: exprToString(t)})
+			}
+			sw.Cases = append(sw.Cases, caseStmt)
+			if bindName != "" && bindName != "_" {
+				// Inside a case naming exactly one type, go/types narrows
+				// the switch variable to that type (recorded as an
+				// implicit object on the *ast.CaseClause itself); a
+				// multi-type or default clause keeps the original
+				// interface type of the asserted expression.
+				dt := a.exprType(tag)
+				init := a.visitExpr(tag)
+				if a.info != nil {
+					if obj, ok := a.info.Implicits[cc]; ok && obj != nil {
+						dt = a.resolveType(obj.Type())
+					}
+				}
+				if len(cc.List) == 1 {
+					// A single-type case narrows the bound variable via a real
+					// type assertion (v := x.(int)) rather than a plain copy of
+					// the tag, which would keep the tag's own interface type.
+					init = &statement{ID: a.newID(), Type: "type-assert", Value: exprToString(cc.List[0]), Left: init}
+				}
+				caseStmt.Block.Stmts = append(caseStmt.Block.Stmts, &statement{
+					ID:       a.newID(),
+					Type:     "variable-declaration",
+					Name:     bindName,
+					DataType: dt,
+					Init:     init,
+				})
+			}
+			a.pushStmts(&caseStmt.Block.Stmts)
+			a.switchDepth++
+			for _, s := range cc.Body {
+				ast.Walk(a, s)
+			}
+			a.switchDepth--
+			a.popStmts()
+		}
+		a.addStmt(sw)
+		return nil
+	case *ast.ReturnStmt:
+		if len(x.Results) > 1 {
+			a.addError(x, fmt.Sprintf("%d", len(x.Results)), "multi-value return not supported")
+			return nil
+		}
+		r := &statement{ID: a.newID(), Line: pos.Line, Type: "return"}
+		if len(x.Results) > 0 {
+			r.Expr = a.visitExpr(x.Results[0])
+		}
+		a.addStmt(r)
+		return nil
+	case *ast.BranchStmt:
+		if x.Label != nil {
+			a.addError(x, x.Label.Name, "labeled branch statements not supported")
+			return nil
+		}
+		var t string
+		switch x.Tok {
+		case token.BREAK:
+			t = "break"
+		case token.CONTINUE:
+			t = "continue"
+		default:
+			a.addError(x, x.Tok.String(), "unsupported branch statement")
+			return nil
+		}
+		if a.loopDepth == 0 && !(t == "break" && a.switchDepth > 0) {
+			a.addError(x, t, t+" outside of a loop")
+			return nil
+		}
+		a.addStmt(&statement{ID: a.newID(), Line: pos.Line, Type: t})
+		return nil
+	case *ast.ImportSpec:
+		// Already validated (and, if invalid, reported) by the *ast.File
+		// case above; nothing further to emit.
 		return nil
 	case *ast.BlockStmt:
 	case *ast.ExprStmt:
 	case *ast.FieldList:
 	case *ast.GenDecl:
-	case *ast.SelectorExpr:
 	default:
-		log.Printf("Ignoring %T\n", node)
+		a.addError(node, fmt.Sprintf("%T", node), "unsupported node type")
 		return nil
 	}
 	a.pushNode(node)
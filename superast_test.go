@@ -2,6 +2,7 @@ package superast
 
 import (
 	"encoding/json"
+	"flag"
 	"go/ast"
 	"go/parser"
 	"go/token"
@@ -9,14 +10,12 @@ import (
 	"os"
 	"path"
 	"testing"
-	"flag"
 )
 
-var write = flag.Bool("write", false, "Write json results")
-
-func init() {
-	flag.Parse()
-}
+var (
+	write = flag.Bool("write", false, "Write json results")
+	name  = flag.String("name", "", "Test name")
+)
 
 func toJSON(t *testing.T, a *AST) []byte {
 	b, err := json.MarshalIndent(a.RootBlock, "", "  ")
@@ -29,18 +28,17 @@ func toJSON(t *testing.T, a *AST) []byte {
 
 const testsDir = "tests"
 
+// doTest translates tests/<name>/<name>.go through a Translator - which
+// picks up every sibling .go file in the directory for cross-file
+// type-checking, the same as the hand-rolled parseTestFiles this replaced -
+// and compares the result against the golden tests/<name>/<name>.json.
 func doTest(t *testing.T, name string) {
-	fset := token.NewFileSet()
-	in, err := os.Open(path.Join(testsDir, name, name+".go"))
+	tr := NewTranslator(ParseFull)
+	a, err := tr.TranslateFile(path.Join(testsDir, name, name+".go"))
 	if err != nil {
-		t.Errorf("Failed opening file: %s", err)
-	}
-	f, err := parser.ParseFile(fset, name+".go", in, 0)
-	if err != nil {
-		t.Errorf("Failed parsing source file: %s", err)
+		t.Errorf("Failed translating %s: %s", name, err)
+		return
 	}
-	a := NewAST(fset)
-	ast.Walk(a, f)
 	got := toJSON(t, a)
 	outPath := path.Join(testsDir, name, name+".json")
 	if *write {
@@ -72,6 +70,10 @@ func TestCases(t *testing.T) {
 	if err != nil {
 		return
 	}
+	if *name != "" {
+		doTest(t, *name)
+		return
+	}
 	for _, e := range entries {
 		if !e.IsDir() {
 			continue
@@ -79,3 +81,193 @@ func TestCases(t *testing.T) {
 		doTest(t, e.Name())
 	}
 }
+
+// TestErrorRecovery feeds the visitor source with constructs it can't
+// translate (a disallowed import, a defer statement) and checks that it
+// records both as errorNodes instead of aborting, while still producing
+// nodes for the rest of main's body.
+func TestErrorRecovery(t *testing.T) {
+	const src = `package main
+
+import "os"
+
+func main() {
+	defer os.Exit(0)
+	x := 1
+	println(x)
+}
+`
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "broken.go", src, 0)
+	if err != nil {
+		t.Fatalf("Failed parsing source: %s", err)
+	}
+	a := NewAST(fset, f)
+	ast.Walk(a, f)
+
+	if len(a.Errors) != 2 {
+		t.Fatalf("Expected 2 errors (bad import + defer), got %d: %+v", len(a.Errors), a.Errors)
+	}
+	if a.Err() == nil {
+		t.Errorf("Err() should report a failure once errors were recorded")
+	}
+
+	// The bad-import errorNode is reported inline, before any declaration
+	// is walked, so it lands ahead of main in RootBlock.Stmts - main isn't
+	// necessarily at index 0.
+	var fn *statement
+	for _, s := range a.RootBlock.Stmts {
+		if st, ok := s.(*statement); ok && st.Type == "function-declaration" && st.Name == "main" {
+			fn = st
+			break
+		}
+	}
+	if fn == nil || fn.Block == nil {
+		t.Fatalf("Expected main's function-declaration to still be produced")
+	}
+	var sawDecl, sawCall bool
+	for _, s := range fn.Block.Stmts {
+		st, ok := s.(*statement)
+		if !ok {
+			continue
+		}
+		switch st.Type {
+		case "variable-declaration":
+			sawDecl = true
+		case "function-call":
+			sawCall = true
+		}
+	}
+	if !sawDecl || !sawCall {
+		t.Errorf("Expected the rest of main's body after the defer error, got decl=%v call=%v", sawDecl, sawCall)
+	}
+}
+
+// TestBranchOutsideLoop checks that a break with no enclosing for/range loop
+// is reported as an errorNode rather than emitted as a bare "break" that
+// wouldn't mean anything to a backend.
+func TestBranchOutsideLoop(t *testing.T) {
+	const src = `package main
+
+func main() {
+	println(1)
+	break
+}
+`
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "broken.go", src, 0)
+	if err != nil {
+		t.Fatalf("Failed parsing source: %s", err)
+	}
+	a := NewAST(fset, f)
+	ast.Walk(a, f)
+
+	if len(a.Errors) != 1 || a.Errors[0].Value != "break" {
+		t.Fatalf("Expected a single 'break' error, got %+v", a.Errors)
+	}
+}
+
+// TestMultiValueReturn checks that a function returning more than one value
+// is reported as errorNodes - one for the declared return type, one for
+// each return statement's results - rather than silently keeping only the
+// first value the way AssignStmt never would for a multi-value assignment.
+func TestMultiValueReturn(t *testing.T) {
+	const src = `package main
+
+func pair() (int, int) {
+	return 1, 2
+}
+`
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "broken.go", src, 0)
+	if err != nil {
+		t.Fatalf("Failed parsing source: %s", err)
+	}
+	a := NewAST(fset, f)
+	ast.Walk(a, f)
+
+	if len(a.Errors) != 2 {
+		t.Fatalf("Expected 2 errors (return type + return statement), got %d: %+v", len(a.Errors), a.Errors)
+	}
+}
+
+// TestFieldListResolvesComposite checks that flattenFieldList resolves a
+// parameter's slice/array/map/pointer/channel type through go/types, the
+// same way a variable declaration's type does - not just the bare
+// Ident/BasicLit/SelectorExpr/StarExpr forms exprToString alone handles.
+func TestFieldListResolvesComposite(t *testing.T) {
+	const src = `package main
+
+func sum(xs []int, p *int) int {
+	return 0
+}
+`
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "in.go", src, 0)
+	if err != nil {
+		t.Fatalf("Failed parsing source: %s", err)
+	}
+	a := NewAST(fset, f)
+	ast.Walk(a, f)
+
+	if len(a.Errors) != 0 {
+		t.Fatalf("Expected no errors, got %+v", a.Errors)
+	}
+	fn, ok := a.RootBlock.Stmts[0].(*statement)
+	if !ok || fn.Type != "function-declaration" {
+		t.Fatalf("Expected a function-declaration, got %+v", a.RootBlock.Stmts[0])
+	}
+	if len(fn.Params) != 2 {
+		t.Fatalf("Expected 2 parameters, got %d", len(fn.Params))
+	}
+	xs := fn.Params[0].DataType
+	if xs == nil || xs.Name != "slice" || xs.SubType == nil || xs.SubType.Name != "int" {
+		t.Errorf("Expected xs's type to be slice of int, got %+v", xs)
+	}
+	p := fn.Params[1].DataType
+	if p == nil || p.Name != "pointer" || p.SubType == nil || p.SubType.Name != "int" {
+		t.Errorf("Expected p's type to be pointer to int, got %+v", p)
+	}
+}
+
+// TestCompositeLitInitError checks that a composite literal used as a
+// variable's initializer - a construct Visit can't translate, so addError
+// records it - still shows up as this variable-declaration's init instead of
+// vanishing into a nil *statement and making the declaration look like it
+// has no initializer at all.
+func TestCompositeLitInitError(t *testing.T) {
+	const src = `package main
+
+func main() {
+	xs := []int{1, 2, 3}
+	_ = xs
+}
+`
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "in.go", src, 0)
+	if err != nil {
+		t.Fatalf("Failed parsing source: %s", err)
+	}
+	a := NewAST(fset, f)
+	ast.Walk(a, f)
+
+	if len(a.Errors) != 1 {
+		t.Fatalf("Expected 1 error for the composite literal, got %+v", a.Errors)
+	}
+	fn, ok := a.RootBlock.Stmts[0].(*statement)
+	if !ok || fn.Type != "function-declaration" {
+		t.Fatalf("Expected a function-declaration, got %+v", a.RootBlock.Stmts[0])
+	}
+	var decl *statement
+	for _, s := range fn.Block.Stmts {
+		if st, ok := s.(*statement); ok && st.Type == "variable-declaration" && st.Name == "xs" {
+			decl = st
+		}
+	}
+	if decl == nil {
+		t.Fatalf("Expected a variable-declaration for xs")
+	}
+	if decl.Init == nil || decl.Init.Type != "error" {
+		t.Errorf("Expected xs's init to be an error node, got %+v", decl.Init)
+	}
+}
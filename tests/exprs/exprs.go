@@ -0,0 +1,11 @@
+package main
+
+func main() {
+	var xs []int
+	a := (1 + 2) * 3
+	b := -a
+	c := !true
+	d := xs[0]
+	e := a + b*d
+	println(a, b, c, d, e)
+}
@@ -0,0 +1,17 @@
+package main
+
+func main() {
+	var xs []int
+	sum := 0
+	for i := 0; i < 10; i++ {
+		sum = 1
+		if i == 5 {
+			break
+		}
+		continue
+	}
+	for _, v := range xs {
+		println(v)
+	}
+	println(sum)
+}
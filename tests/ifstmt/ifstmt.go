@@ -0,0 +1,13 @@
+package main
+
+func main() {
+	x := 1
+	if x > 0 {
+		x = 2
+	} else if x < 0 {
+		x = 3
+	} else {
+		x = 0
+	}
+	println(x)
+}
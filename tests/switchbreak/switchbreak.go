@@ -0,0 +1,12 @@
+package main
+
+func main() {
+	x := 1
+	switch x {
+	case 1:
+		println(x)
+		break
+	default:
+		println(0)
+	}
+}
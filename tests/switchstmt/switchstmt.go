@@ -0,0 +1,14 @@
+package main
+
+func main() {
+	x := 1
+	switch x {
+	case 1, 2:
+		x = 1
+	case 3:
+		x = 3
+	default:
+		x = 0
+	}
+	println(x)
+}
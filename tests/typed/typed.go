@@ -0,0 +1,6 @@
+package main
+
+func main() {
+	x := add(1, 2)
+	println(x)
+}
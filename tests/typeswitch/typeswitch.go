@@ -0,0 +1,13 @@
+package main
+
+func main() {
+	var x interface{} = 1
+	switch v := x.(type) {
+	case int:
+		println(v)
+	case string:
+		println(v)
+	default:
+		println(0)
+	}
+}
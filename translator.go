@@ -0,0 +1,173 @@
+package superast
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ParseMode controls how much of a source file a Translator walks.
+type ParseMode int
+
+const (
+	// ParseFull walks every declaration and statement, the Translator's
+	// default.
+	ParseFull ParseMode = iota
+	// ParseHeader keeps function signatures but leaves their bodies
+	// unwalked, for callers that only need the package's shape.
+	ParseHeader
+	// ParseExported walks like ParseFull but skips function declarations
+	// whose name isn't exported; type declarations are kept either way.
+	ParseExported
+)
+
+// fileKey identifies a cached *AST: a file re-translates only when its path,
+// size or modification time changes, or when it's asked for under a
+// different ParseMode.
+type fileKey struct {
+	path  string
+	mtime time.Time
+	size  int64
+	mode  ParseMode
+}
+
+// cacheEntry holds everything translate needs to reuse a file without
+// reparsing it: the *AST it produced, and the *ast.File that produced it, so
+// an unrelated stale file in the same package can still be type-checked
+// alongside it without a reparse of its own.
+type cacheEntry struct {
+	file *ast.File
+	ast  *AST
+}
+
+// Translator parses and walks Go source into super-ASTs, caching the result
+// per file so a long-running caller (or a future language-server
+// integration) doesn't redo work for files that haven't changed on disk -
+// the same trick gopls's parseGoHandle/parseGoData cache uses.
+type Translator struct {
+	// Mode is applied to every file this Translator walks.
+	Mode ParseMode
+
+	fset  *token.FileSet
+	cache map[fileKey]*cacheEntry
+}
+
+// NewTranslator returns a Translator that parses with mode and shares a
+// single *token.FileSet across every file it translates, so positions stay
+// comparable between them.
+func NewTranslator(mode ParseMode) *Translator {
+	return &Translator{
+		Mode:  mode,
+		fset:  token.NewFileSet(),
+		cache: make(map[fileKey]*cacheEntry),
+	}
+}
+
+// packageFiles lists the non-test .go files directly inside dir, in
+// directory order. It doesn't recurse: a Translator translates one package
+// directory at a time.
+func (t *Translator) packageFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var paths []string
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		paths = append(paths, filepath.Join(dir, name))
+	}
+	return paths, nil
+}
+
+// translate parses every file in paths that isn't already cached,
+// type-checks them together as a single package (so cross-file references
+// resolve), and returns their super-ASTs in the same order - a file whose
+// path, size and modification time still match its cache entry is returned
+// without being reparsed or re-type-checked at all.
+func (t *Translator) translate(paths []string) ([]*AST, error) {
+	keys := make([]fileKey, len(paths))
+	asts := make([]*AST, len(paths))
+	entries := make([]*cacheEntry, len(paths))
+	allCached := true
+	for i, p := range paths {
+		fi, err := os.Stat(p)
+		if err != nil {
+			return nil, err
+		}
+		keys[i] = fileKey{path: p, mtime: fi.ModTime(), size: fi.Size(), mode: t.Mode}
+		if e, ok := t.cache[keys[i]]; ok {
+			entries[i] = e
+			asts[i] = e.ast
+		} else {
+			allCached = false
+		}
+	}
+	if allCached {
+		return asts, nil
+	}
+
+	// Only parse the files that aren't already cached - a cache hit keeps
+	// the *ast.File it was built from, which is all NewAST needs from it to
+	// type-check the rest of the package.
+	files := make([]*ast.File, len(paths))
+	for i, p := range paths {
+		if entries[i] != nil {
+			files[i] = entries[i].file
+			continue
+		}
+		f, err := parser.ParseFile(t.fset, p, nil, parser.ParseComments)
+		if err != nil {
+			return nil, fmt.Errorf("parse %s: %w", p, err)
+		}
+		files[i] = f
+	}
+	for i := range paths {
+		if entries[i] != nil {
+			continue
+		}
+		a := NewAST(t.fset, files...)
+		a.Mode = t.Mode
+		ast.Walk(a, files[i])
+		t.cache[keys[i]] = &cacheEntry{file: files[i], ast: a}
+		asts[i] = a
+	}
+	return asts, nil
+}
+
+// TranslateDir translates every file in dir's package, returning one *AST
+// per file in directory order.
+func (t *Translator) TranslateDir(dir string) ([]*AST, error) {
+	paths, err := t.packageFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	return t.translate(paths)
+}
+
+// TranslateFile translates path, type-checked alongside the rest of its
+// package directory so cross-file references still resolve, and returns its
+// super-AST.
+func (t *Translator) TranslateFile(path string) (*AST, error) {
+	paths, err := t.packageFiles(filepath.Dir(path))
+	if err != nil {
+		return nil, err
+	}
+	asts, err := t.translate(paths)
+	if err != nil {
+		return nil, err
+	}
+	for i, p := range paths {
+		if p == path {
+			return asts[i], nil
+		}
+	}
+	return nil, fmt.Errorf("superast: %s not found in its own package directory", path)
+}
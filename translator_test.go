@@ -0,0 +1,106 @@
+package superast
+
+import (
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"testing"
+)
+
+// TestTranslatorCachesUnchangedFile checks that a second TranslateFile call
+// for a file that hasn't changed on disk returns the exact same *AST rather
+// than re-parsing and re-walking it.
+func TestTranslatorCachesUnchangedFile(t *testing.T) {
+	tr := NewTranslator(ParseFull)
+	p := path.Join(testsDir, "exprs", "exprs.go")
+
+	first, err := tr.TranslateFile(p)
+	if err != nil {
+		t.Fatalf("Failed translating %s: %s", p, err)
+	}
+	second, err := tr.TranslateFile(p)
+	if err != nil {
+		t.Fatalf("Failed translating %s a second time: %s", p, err)
+	}
+	if first != second {
+		t.Errorf("Expected the second TranslateFile call to reuse the cached *AST, got a distinct one")
+	}
+}
+
+// TestTranslatorCachesDir checks that TranslateDir, like TranslateFile,
+// reuses cached *ASTs for a package whose files haven't changed.
+func TestTranslatorCachesDir(t *testing.T) {
+	tr := NewTranslator(ParseFull)
+	dir := path.Join(testsDir, "exprs")
+
+	first, err := tr.TranslateDir(dir)
+	if err != nil {
+		t.Fatalf("Failed translating %s: %s", dir, err)
+	}
+	second, err := tr.TranslateDir(dir)
+	if err != nil {
+		t.Fatalf("Failed translating %s a second time: %s", dir, err)
+	}
+	if len(first) != len(second) {
+		t.Fatalf("Expected the same number of files both times, got %d and %d", len(first), len(second))
+	}
+	for i := range first {
+		if first[i] != second[i] {
+			t.Errorf("Expected TranslateDir's second call to reuse the cached *AST for file %d, got a distinct one", i)
+		}
+	}
+}
+
+// TestTranslatorReparsesOnlyStaleFile checks that when only one file in a
+// multi-file package changes, a re-translate reparses just that file -
+// not the whole package, which used to happen because the parse loop in
+// translate had no cache-miss guard of its own.
+func TestTranslatorReparsesOnlyStaleFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "superast-translator")
+	if err != nil {
+		t.Fatalf("Failed creating temp dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	aPath := filepath.Join(dir, "a.go")
+	bPath := filepath.Join(dir, "b.go")
+	if err := ioutil.WriteFile(aPath, []byte("package foo\n\nfunc A() int { return 1 }\n"), 0o644); err != nil {
+		t.Fatalf("Failed writing a.go: %s", err)
+	}
+	if err := ioutil.WriteFile(bPath, []byte("package foo\n\nfunc B() int { return 2 }\n"), 0o644); err != nil {
+		t.Fatalf("Failed writing b.go: %s", err)
+	}
+
+	tr := NewTranslator(ParseFull)
+	if _, err := tr.TranslateDir(dir); err != nil {
+		t.Fatalf("Failed translating %s: %s", dir, err)
+	}
+
+	before := 0
+	tr.fset.Iterate(func(f *token.File) bool {
+		before++
+		return true
+	})
+
+	// Change b.go's size so its fileKey no longer matches the cache entry,
+	// while leaving a.go untouched.
+	if err := ioutil.WriteFile(bPath, []byte("package foo\n\nfunc B() int { return 22 }\n"), 0o644); err != nil {
+		t.Fatalf("Failed rewriting b.go: %s", err)
+	}
+
+	if _, err := tr.TranslateDir(dir); err != nil {
+		t.Fatalf("Failed re-translating %s: %s", dir, err)
+	}
+
+	after := 0
+	tr.fset.Iterate(func(f *token.File) bool {
+		after++
+		return true
+	})
+
+	if got, want := after-before, 1; got != want {
+		t.Errorf("Expected only the stale file to be reparsed into fset (1 new *token.File), got %d new entries", got)
+	}
+}